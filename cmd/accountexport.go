@@ -0,0 +1,228 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+)
+
+var accountExportOpts struct {
+	listType   string
+	outputFile string
+	appendFile bool
+	noHeaders  bool
+}
+
+// Maximum number of account IDs per relationships API query
+const relationshipsBatchSize = 40
+
+var accountExportSubcommand = &cobra.Command{
+	Use:   "export [--type following|followers|blocks|mutes]",
+	Short: "Export account lists to CSV",
+	Long: `Export account lists to CSV
+
+The output uses the CSV format of the Mastodon import/export page, so that
+it can be imported into another instance.
+Use --all to export the whole list.
+With --append, the accounts are added at the end of the --output-file file
+(the header line is not written again if the file is not empty).
+When the following list of another account is exported (--account-id), the
+"Show boosts" column is not available and is omitted.`,
+	Example: `  madonctl account export --all > following_accounts.csv
+  madonctl account export --all --type blocks --output-file blocked_accounts.csv
+  madonctl account export --all --type mutes --output-file muted_accounts.csv
+  madonctl account export --no-headers --account-id Gargron@mastodon.social >> following_accounts.csv
+  madonctl account export --account-id Gargron@mastodon.social --output-file following_accounts.csv --append`,
+	RunE: accountExportRunE,
+}
+
+func init() {
+	accountsCmd.AddCommand(accountExportSubcommand)
+
+	accountExportSubcommand.Flags().StringVar(&accountExportOpts.listType, "type", "following", "List type (following|followers|blocks|mutes)")
+	accountExportSubcommand.Flags().StringVar(&accountExportOpts.outputFile, "output-file", "", "Write to file instead of standard output")
+	accountExportSubcommand.Flags().BoolVar(&accountExportOpts.appendFile, "append", false, "Append to the output file instead of truncating it")
+	accountExportSubcommand.Flags().BoolVar(&accountExportOpts.noHeaders, "no-headers", false, "Do not write the CSV header line")
+}
+
+func accountExportRunE(cmd *cobra.Command, args []string) error {
+	opt := accountExportOpts
+	accOpt := accountsOpts
+
+	switch opt.listType {
+	case "following", "followers", "blocks", "mutes":
+	default:
+		return errors.Errorf("invalid list type '%s'", opt.listType)
+	}
+	if opt.appendFile && opt.outputFile == "" {
+		return errors.New("--append requires --output-file")
+	}
+
+	var limOpts *madon.LimitParams
+	if accOpt.all || accOpt.limit > 0 {
+		limOpts = new(madon.LimitParams)
+		limOpts.All = accOpt.all
+	}
+	if accOpt.limit > 0 {
+		limOpts.Limit = int(accOpt.limit)
+	}
+
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	// ownList is true if the list is the current user's list, i.e. if the
+	// relationships of the current user apply to the list accounts.
+	ownList := true
+	accountID := accOpt.accountID
+	if accountID != "" {
+		var err error
+		if accountID, err = resolveAccount(accountID); err != nil {
+			errPrint("Cannot find user '%s': %v", accOpt.accountID, err)
+			os.Exit(1)
+		}
+	}
+	if opt.listType == "following" || opt.listType == "followers" {
+		account, err := gClient.GetCurrentAccount()
+		if err != nil {
+			return err
+		}
+		if accountID == "" {
+			accountID = account.ID
+		}
+		ownList = accountID == account.ID
+	}
+	// The "Show boosts" column is only available for the user's own list
+	showBoosts := opt.listType == "following" && ownList
+
+	var accountList []madon.Account
+	var err error
+
+	switch opt.listType {
+	case "following":
+		accountList, err = gClient.GetAccountFollowing(accountID, limOpts)
+	case "followers":
+		accountList, err = gClient.GetAccountFollowers(accountID, limOpts)
+	case "blocks":
+		accountList, err = gClient.GetBlockedAccounts(limOpts)
+	case "mutes":
+		accountList, err = gClient.GetMutedAccounts(limOpts)
+	}
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	if accOpt.keep > 0 && len(accountList) > int(accOpt.keep) {
+		accountList = accountList[:accOpt.keep]
+	}
+
+	// Relationships are needed for the extra columns
+	var relationships map[madon.ActivityID]madon.Relationship
+	if showBoosts || opt.listType == "mutes" {
+		if relationships, err = getRelationshipMap(accountList); err != nil {
+			errPrint("Error: %s", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	noHeaders := opt.noHeaders
+	if opt.outputFile != "" {
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if opt.appendFile {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		f, err := os.OpenFile(opt.outputFile, flags, 0644)
+		if err != nil {
+			return errors.Wrap(err, "cannot create output file")
+		}
+		defer f.Close()
+		out = f
+		if opt.appendFile {
+			// Do not repeat the header line
+			if fi, err := f.Stat(); err == nil && fi.Size() > 0 {
+				noHeaders = true
+			}
+		}
+	}
+
+	w := csv.NewWriter(out)
+	if !noHeaders {
+		switch opt.listType {
+		case "following":
+			if showBoosts {
+				w.Write([]string{"Account address", "Show boosts"})
+			} else {
+				w.Write([]string{"Account address"})
+			}
+		case "followers":
+			w.Write([]string{"Account address"})
+		case "mutes":
+			w.Write([]string{"Account address", "Hide notifications"})
+		}
+	}
+	for _, a := range accountList {
+		addr := accountAddress(&a)
+		switch opt.listType {
+		case "following":
+			if !showBoosts {
+				w.Write([]string{addr})
+				break
+			}
+			r := relationships[a.ID]
+			w.Write([]string{addr, strconv.FormatBool(r.ShowingReblogs)})
+		case "mutes":
+			r := relationships[a.ID]
+			w.Write([]string{addr, strconv.FormatBool(r.MutingNotifications)})
+		default:
+			w.Write([]string{addr})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// accountAddress returns the full user@domain address of an account
+func accountAddress(a *madon.Account) string {
+	if strings.ContainsRune(a.Acct, '@') {
+		return a.Acct
+	}
+	// Local account: add the instance domain name
+	if u, err := url.Parse(gClient.InstanceURL); err == nil && u.Host != "" {
+		return a.Acct + "@" + u.Host
+	}
+	return a.Acct
+}
+
+// getRelationshipMap returns the relationships with the accounts, indexed
+// by account ID
+func getRelationshipMap(accountList []madon.Account) (map[madon.ActivityID]madon.Relationship, error) {
+	relationships := make(map[madon.ActivityID]madon.Relationship)
+	for i := 0; i < len(accountList); i += relationshipsBatchSize {
+		var ids []madon.ActivityID
+		for j := i; j < len(accountList) && j < i+relationshipsBatchSize; j++ {
+			ids = append(ids, accountList[j].ID)
+		}
+		rl, err := gClient.GetAccountRelationships(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rl {
+			relationships[r.ID] = r
+		}
+	}
+	return relationships, nil
+}
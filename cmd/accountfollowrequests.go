@@ -0,0 +1,97 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+)
+
+var accountFollowRequestsAcceptSubcommand = &cobra.Command{
+	Use:   "accept --account-ids ID1,ID2... | --all",
+	Short: "Accept several follow requests",
+	Example: `  madonctl account follow-requests accept --account-ids 1234,5678
+  madonctl account follow-requests accept --all`,
+	RunE: followRequestsBulkRunE,
+}
+
+var accountFollowRequestsRejectSubcommand = &cobra.Command{
+	Use:   "reject --account-ids ID1,ID2... | --all",
+	Short: "Reject several follow requests",
+	Example: `  madonctl account follow-requests reject --account-ids 1234,5678
+  madonctl account follow-requests reject --all`,
+	RunE: followRequestsBulkRunE,
+}
+
+func init() {
+	accountFollowRequestsSubcommand.AddCommand(accountFollowRequestsAcceptSubcommand,
+		accountFollowRequestsRejectSubcommand)
+
+	accountFollowRequestsAcceptSubcommand.Flags().StringVar(&accountsOpts.accountIDs, "account-ids", "", "Comma-separated list of account IDs")
+	accountFollowRequestsRejectSubcommand.Flags().StringVar(&accountsOpts.accountIDs, "account-ids", "", "Comma-separated list of account IDs")
+}
+
+func followRequestsBulkRunE(cmd *cobra.Command, args []string) error {
+	opt := accountsOpts
+	accept := cmd.Name() == "accept"
+
+	ids, err := splitIDs(opt.accountIDs)
+	if err != nil {
+		return errors.New("cannot parse account IDs")
+	}
+	if opt.accountID != "" { // Allow --account-id
+		ids = append(ids, opt.accountID)
+	}
+	if opt.all && len(ids) > 0 {
+		return errors.New("cannot use both --all and account IDs")
+	}
+	if !opt.all && len(ids) == 0 {
+		return errors.New("missing account IDs (or --all)")
+	}
+
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	if opt.all {
+		requests, err := gClient.GetAccountFollowRequests(&madon.LimitParams{All: true})
+		if err != nil {
+			errPrint("Error: %s", err.Error())
+			os.Exit(1)
+		}
+		for _, a := range requests {
+			ids = append(ids, a.ID)
+		}
+	}
+
+	var nOK, nFailed int
+	for _, id := range ids {
+		if err := gClient.FollowRequestAuthorize(id, accept); err != nil {
+			errPrint("Error: %s: %s", id, err.Error())
+			nFailed++
+			continue
+		}
+		if verbose {
+			errPrint("%s: OK", id)
+		}
+		nOK++
+	}
+
+	action := "accepted"
+	if !accept {
+		action = "rejected"
+	}
+	errPrint("%d follow request(s) %s, %d failure(s)", nOK, action, nFailed)
+
+	if nFailed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
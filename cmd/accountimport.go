@@ -0,0 +1,182 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var accountImportOpts struct {
+	listType        string
+	unfollow        bool
+	continueOnError bool
+}
+
+var accountImportSubcommand = &cobra.Command{
+	Use:   "import [--type following|mutes|blocks] FILE",
+	Short: "Follow (or unfollow), mute or block a list of accounts",
+	Long: `Follow (or unfollow), mute or block a list of accounts
+
+The file should contain one account address (user@domain) per line, or use the
+CSV format of the Mastodon import/export page.  Use '-' to read the list from
+the standard input.
+
+When a Mastodon CSV file is used, the "Show boosts" column (following list)
+or the "Hide notifications" column (mutes list) is honored.`,
+	Example: `  madonctl account import following_accounts.csv
+  madonctl account import --continue-on-error accounts.txt
+  madonctl account import --unfollow - < accounts.txt
+  madonctl account import --type mutes muted_accounts.csv
+  madonctl account import --type blocks blocked_accounts.csv`,
+	RunE: accountImportRunE,
+}
+
+func init() {
+	accountsCmd.AddCommand(accountImportSubcommand)
+
+	accountImportSubcommand.Flags().StringVar(&accountImportOpts.listType, "type", "following", "List type (following|mutes|blocks)")
+	accountImportSubcommand.Flags().BoolVar(&accountImportOpts.unfollow, "unfollow", false, "Unfollow the accounts")
+	accountImportSubcommand.Flags().BoolVar(&accountImportOpts.continueOnError, "continue-on-error", false, "Do not stop at the first error")
+}
+
+// importEntry is an account address read from an import file
+// The option is the boolean value of the second CSV column, if any ("Show
+// boosts" for the following list, "Hide notifications" for the mutes list).
+type importEntry struct {
+	address string
+	option  *bool
+}
+
+func accountImportRunE(cmd *cobra.Command, args []string) error {
+	opt := accountImportOpts
+
+	if len(args) != 1 {
+		return errors.New("wrong usage: import needs 1 argument")
+	}
+
+	switch opt.listType {
+	case "following":
+	case "mutes", "blocks":
+		if opt.unfollow {
+			return errors.New("--unfollow can only be used with the following list")
+		}
+	default:
+		return errors.Errorf("invalid list type '%s'", opt.listType)
+	}
+
+	var in io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return errors.Wrap(err, "cannot open input file")
+		}
+		defer f.Close()
+		in = f
+	}
+
+	entries, err := readImportList(in)
+	if err != nil {
+		return errors.Wrap(err, "cannot read account list")
+	}
+
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	var nOK, nFailed int
+	for _, e := range entries {
+		if err := accountImportEntry(e, opt.listType, opt.unfollow); err != nil {
+			errPrint("Error: %s: %s", e.address, err.Error())
+			nFailed++
+			if !opt.continueOnError {
+				break
+			}
+			continue
+		}
+		if verbose {
+			errPrint("%s: OK", e.address)
+		}
+		nOK++
+	}
+
+	action := "followed"
+	switch {
+	case opt.unfollow:
+		action = "unfollowed"
+	case opt.listType == "mutes":
+		action = "muted"
+	case opt.listType == "blocks":
+		action = "blocked"
+	}
+	errPrint("%d account(s) %s, %d failure(s), %d skipped", nOK, action, nFailed,
+		len(entries)-nOK-nFailed)
+
+	if nFailed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// readImportList reads account addresses from a plain list or a Mastodon
+// CSV file
+func readImportList(in io.Reader) ([]importEntry, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var entries []importEntry
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 {
+			continue
+		}
+		addr := strings.TrimLeft(strings.TrimSpace(record[0]), "@")
+		if addr == "" || addr == "Account address" || strings.HasPrefix(addr, "#") {
+			continue // Skip empty lines, header and comments
+		}
+		e := importEntry{address: addr}
+		if len(record) > 1 {
+			if b, err := strconv.ParseBool(strings.TrimSpace(record[1])); err == nil {
+				e.option = &b
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// accountImportEntry resolves the account address and follows/unfollows,
+// mutes or blocks it, depending on the list type
+func accountImportEntry(e importEntry, listType string, unfollow bool) error {
+	accountID, err := resolveAccount(e.address)
+	if err != nil {
+		return err
+	}
+	switch {
+	case listType == "mutes":
+		_, err = gClient.MuteAccount(accountID, e.option)
+	case listType == "blocks":
+		_, err = gClient.BlockAccount(accountID)
+	case unfollow:
+		_, err = gClient.UnfollowAccount(accountID)
+	default:
+		_, err = gClient.FollowAccount(accountID, e.option)
+	}
+	return err
+}
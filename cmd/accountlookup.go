@@ -0,0 +1,63 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var accountLookupOpts struct {
+	acct string
+}
+
+var accountLookupSubcommand = &cobra.Command{
+	Use:   "lookup --acct user@domain",
+	Short: "Look up an account by its address",
+	Long: `Look up an account by its address
+
+Unlike the search subcommand, the lookup endpoint only returns the account
+with the exact given address, or an error if there is no such account.
+Use the user name alone for a local account.
+This requires Mastodon 3.4 or later.`,
+	Example: `  madonctl account lookup --acct Gargron@mastodon.social
+  madonctl account lookup --acct Gargron --template '{{.id}}{{"\n"}}'`,
+	RunE: accountLookupRunE,
+}
+
+func init() {
+	accountsCmd.AddCommand(accountLookupSubcommand)
+
+	accountLookupSubcommand.Flags().StringVar(&accountLookupOpts.acct, "acct", "", "Account address (user or user@domain)")
+}
+
+func accountLookupRunE(cmd *cobra.Command, args []string) error {
+	acct := strings.TrimPrefix(strings.TrimSpace(accountLookupOpts.acct), "@")
+	if acct == "" {
+		return errors.New("missing account address")
+	}
+
+	// We don't have to log in
+	if err := madonInit(false); err != nil {
+		return err
+	}
+
+	account, err := gClient.AccountLookup(acct)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return p.printObj(account)
+}
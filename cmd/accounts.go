@@ -6,15 +6,19 @@
 package cmd
 
 import (
+	"bufio"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 var accountUpdateFlags, accountMuteFlags, accountFollowFlags *flag.FlagSet
@@ -27,8 +31,10 @@ var accountsOpts struct {
 	all                   bool             // Try to fetch all results
 	onlyMedia, onlyPinned bool             // For acccount statuses
 	excludeReplies        bool             // For acccount statuses
+	excludeReblogs        bool             // For acccount statuses
+	tagged                string           // For acccount statuses
 	remoteUID             string           // For account follow
-	reblogs               bool             // For account follow
+	reblogs, notify       bool             // For account follow
 	acceptFR, rejectFR    bool             // For account follow_requests
 	list                  bool             // For account follow_requests/reports
 	accountIDs            string           // For account relationships
@@ -41,7 +47,9 @@ var accountsOpts struct {
 	defaultPrivacy        string           // For account update
 	defaultSensitive      bool             // For account update
 	locked, bot           bool             // For account update
+	discoverable          bool             // For account update
 	muteNotifications     bool             // For account mute
+	muteDuration          string           // For account mute
 	following             bool             // For account search
 }
 
@@ -53,6 +61,7 @@ func init() {
 
 	// Global flags
 	accountsCmd.PersistentFlags().StringVarP(&accountsOpts.accountID, "account-id", "a", "", "Account ID number")
+	accountsCmd.RegisterFlagCompletionFunc("account-id", completeAccountIDs)
 	accountsCmd.PersistentFlags().StringVarP(&accountsOpts.accountUID, "user-id", "u", "", "Account user ID")
 	accountsCmd.PersistentFlags().UintVarP(&accountsOpts.limit, "limit", "l", 0, "Limit number of API results")
 	accountsCmd.PersistentFlags().UintVarP(&accountsOpts.keep, "keep", "k", 0, "Limit number of results")
@@ -64,16 +73,23 @@ func init() {
 	accountStatusesSubcommand.Flags().BoolVar(&accountsOpts.onlyPinned, "pinned", false, "Only statuses that have been pinned")
 	accountStatusesSubcommand.Flags().BoolVar(&accountsOpts.onlyMedia, "only-media", false, "Only statuses with media attachments")
 	accountStatusesSubcommand.Flags().BoolVar(&accountsOpts.excludeReplies, "exclude-replies", false, "Exclude replies to other statuses")
+	accountStatusesSubcommand.Flags().BoolVar(&accountsOpts.excludeReblogs, "exclude-reblogs", false, "Exclude reblogs (boosts)")
+	accountStatusesSubcommand.Flags().StringVar(&accountsOpts.tagged, "tagged", "", "Only statuses with the given hashtag")
 
 	accountFollowRequestsSubcommand.Flags().BoolVar(&accountsOpts.list, "list", false, "List pending follow requests")
 	accountFollowRequestsSubcommand.Flags().BoolVar(&accountsOpts.acceptFR, "accept", false, "Accept the follow request from the account ID")
 	accountFollowRequestsSubcommand.Flags().BoolVar(&accountsOpts.rejectFR, "reject", false, "Reject the follow request from the account ID")
 
 	accountMuteSubcommand.Flags().BoolVarP(&accountsOpts.muteNotifications, "notifications", "", true, "Mute the notifications")
-	accountFollowSubcommand.Flags().BoolVarP(&accountsOpts.reblogs, "show-reblogs", "", true, "Follow account's boosts")
+	accountMuteSubcommand.Flags().StringVar(&accountsOpts.muteDuration, "duration", "", "Mute duration (seconds or duration string, e.g. 2h30m; default: indefinite)")
+	accountFollowSubcommand.Flags().BoolVar(&accountsOpts.reblogs, "reblogs", true, "Show account's boosts in the home timeline")
+	accountFollowSubcommand.Flags().BoolVar(&accountsOpts.reblogs, "show-reblogs", true, "Follow account's boosts")
+	accountFollowSubcommand.Flags().MarkHidden("show-reblogs") // Kept for compatibility
+	accountFollowSubcommand.Flags().BoolVar(&accountsOpts.notify, "notify", false, "Get notified when the account posts a status")
 	accountFollowSubcommand.Flags().StringVarP(&accountsOpts.remoteUID, "remote", "r", "", "Follow remote account (user@domain)")
 
 	accountRelationshipsSubcommand.Flags().StringVar(&accountsOpts.accountIDs, "account-ids", "", "Comma-separated list of account IDs")
+	accountFamiliarFollowersSubcommand.Flags().StringVar(&accountsOpts.accountIDs, "account-ids", "", "Comma-separated list of account IDs")
 
 	accountReportsSubcommand.Flags().StringVar(&accountsOpts.statusIDs, "status-ids", "", "Comma-separated list of status IDs")
 	accountReportsSubcommand.Flags().StringVar(&accountsOpts.comment, "comment", "", "Report comment")
@@ -85,12 +101,30 @@ func init() {
 	accountUpdateSubcommand.Flags().StringVar(&accountsOpts.note, "note", "", "User note (a.k.a. bio)")
 	accountUpdateSubcommand.Flags().StringVar(&accountsOpts.avatar, "avatar", "", "User avatar image")
 	accountUpdateSubcommand.Flags().StringVar(&accountsOpts.header, "header", "", "User header image")
-	accountUpdateSubcommand.Flags().StringArrayVar(&accountsOpts.profileFields, "profile-field", nil, "Profile metadata field (NAME=VALUE)")
+	accountUpdateSubcommand.Flags().StringArrayVar(&accountsOpts.profileFields, "field", nil, "Profile metadata field (NAME=VALUE; can be repeated)")
 	accountUpdateSubcommand.Flags().StringVar(&accountsOpts.defaultLanguage, "default-language", "", "Default toots language (iso 639 code)")
 	accountUpdateSubcommand.Flags().StringVar(&accountsOpts.defaultPrivacy, "default-privacy", "", "Default toot privacy (public, unlisted, private)")
 	accountUpdateSubcommand.Flags().BoolVar(&accountsOpts.defaultSensitive, "default-sensitive", false, "Mark medias as sensitive by default")
 	accountUpdateSubcommand.Flags().BoolVar(&accountsOpts.locked, "locked", false, "Following account requires approval")
 	accountUpdateSubcommand.Flags().BoolVar(&accountsOpts.bot, "bot", false, "Set as service (automated) account")
+	accountUpdateSubcommand.Flags().BoolVar(&accountsOpts.discoverable, "discoverable", false, "Feature the account in the profile directory")
+	// --profile-field is kept for compatibility
+	accountUpdateSubcommand.Flags().SetNormalizeFunc(func(f *flag.FlagSet, name string) flag.NormalizedName {
+		if name == "profile-field" {
+			name = "field"
+		}
+		return flag.NormalizedName(name)
+	})
+
+	// Dynamic completion of the account argument
+	for _, c := range accountsCmd.Commands() {
+		switch c.Name() {
+		case "show", "followers", "following", "statuses",
+			"follow", "unfollow", "block", "unblock",
+			"mute", "unmute", "pin", "unpin":
+			c.ValidArgsFunction = completeAccountIDArg
+		}
+	}
 
 	// Those variables will be used to check if the options were
 	// explicitly set or not
@@ -105,7 +139,14 @@ var accountsCmd = &cobra.Command{
 	Use:     "account [--account-id ID] subcommand",
 	Aliases: []string{"accounts"},
 	Short:   "Account-related functions",
-	//Long:    `TBW...`, // TODO
+	Long: `Account-related functions
+
+The account can be given with --account-id (or --user-id), or as an argument.
+With the account ID "-", the account IDs (or handles) are read from the
+standard input, one per line, and the subcommand is run for each of them.`,
+	Example: `  madonctl account show Gargron@mastodon.social
+  madonctl account search foo -o template --template '{{.id}}{{"\n"}}' | madonctl account follow -
+  madonctl account --account-id - mute < accounts.txt`,
 }
 
 // Note: Some account subcommands are not defined in this file.
@@ -156,6 +197,9 @@ If no account ID is specified, the current user account is used.`,
 		Use:     "blocks",
 		Aliases: []string{"blocked"},
 		Short:   "Display the user's blocked accounts",
+		Example: `  madonctl account blocks
+  madonctl account blocks --all
+  madonctl account blocks --limit 80 --keep 50`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return accountSubcommandsRunE(cmd.Name(), args)
 		},
@@ -164,6 +208,8 @@ If no account ID is specified, the current user account is used.`,
 		Use:     "mutes",
 		Aliases: []string{"muted"},
 		Short:   "Display the user's muted accounts",
+		Example: `  madonctl account mutes
+  madonctl account mutes --all`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return accountSubcommandsRunE(cmd.Name(), args)
 		},
@@ -180,6 +226,7 @@ If no account ID is specified, the current user account is used.`,
 	accountPinSubcommand,
 	accountUnpinSubcommand,
 	accountRelationshipsSubcommand,
+	accountFamiliarFollowersSubcommand,
 	accountReportsSubcommand,
 	accountUpdateSubcommand,
 	accountListEndorsementsSubcommand,
@@ -206,6 +253,9 @@ var accountStatusesSubcommand = &cobra.Command{
   madonctl account statuses @McKael                     # local account
   madonctl account statuses Gargron@mastodon.social     # remote (known account)
   madonctl account statuses https://mastodon.social/@Gargron  # any account URL
+  madonctl account statuses --exclude-replies --exclude-reblogs
+  madonctl account statuses --pinned
+  madonctl account statuses --tagged golang --only-media
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return accountSubcommandsRunE(cmd.Name(), args)
@@ -217,8 +267,11 @@ var accountFollowRequestsSubcommand = &cobra.Command{
 	Aliases: []string{"follow-request", "fr"},
 	Short:   "List, accept or deny a follow request",
 	Example: `  madonctl account follow-requests --list
+  madonctl account follow-requests --list --all
   madonctl account follow-requests --account-id X --accept
-  madonctl account follow-requests --account-id Y --reject`,
+  madonctl account follow-requests --account-id Y --reject
+  madonctl account follow-requests accept --account-ids X,Y,Z
+  madonctl account follow-requests reject --all`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return accountSubcommandsRunE(cmd.Name(), args)
 	},
@@ -232,7 +285,8 @@ var accountFollowSubcommand = &cobra.Command{
 
 # Or argument type can be guessed:
   madonctl account follow 4800
-  madonctl account follow Gargron@mastodon.social --show-reblogs=false
+  madonctl account follow Gargron@mastodon.social --reblogs=false
+  madonctl account follow Gargron@mastodon.social --notify
   madonctl account follow https://mastodon.social/@Gargron
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -271,6 +325,9 @@ var accountUnblockSubcommand = &cobra.Command{
 var accountMuteSubcommand = &cobra.Command{
 	Use:   "mute",
 	Short: "Mute the account",
+	Example: `  madonctl account mute --account-id 1234
+  madonctl account mute --notifications=false user@example.com
+  madonctl account mute --duration 3h user@example.com`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return accountSubcommandsRunE(cmd.Name(), args)
 	},
@@ -314,6 +371,22 @@ var accountListEndorsementsSubcommand = &cobra.Command{
 var accountRelationshipsSubcommand = &cobra.Command{
 	Use:   "relationships --account-ids ACC1,ACC2...",
 	Short: "List relationships with the accounts",
+	Long: `List relationships with the accounts
+
+The relationships with all the accounts are fetched with a single API request.`,
+	Example: `  madonctl account relationships --account-id 1234
+  madonctl account relationships --account-ids 1234,5678,9012
+  madonctl account relationships --account-ids 1234,5678 --template '{{.id}} {{.followed_by}}{{"\n"}}'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return accountSubcommandsRunE(cmd.Name(), args)
+	},
+}
+
+var accountFamiliarFollowersSubcommand = &cobra.Command{
+	Use:   "familiar-followers --account-ids ACC1,ACC2...",
+	Short: "List the accounts you follow that also follow the accounts",
+	Example: `  madonctl account familiar-followers --account-id 1234
+  madonctl account familiar-followers --account-ids 1234,5678`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return accountSubcommandsRunE(cmd.Name(), args)
 	},
@@ -335,13 +408,19 @@ var accountUpdateSubcommand = &cobra.Command{
 	Long: `Update connected user account
 
 All flags are optional (set to an empty string if you want to delete a field).
-The options --avatar and --header should be paths to image files.
+The options --avatar and --header should be paths to image files (they are
+checked before the request is sent).
+The --field option can be repeated; the profile metadata fields are replaced
+with the given list.
 
 Please note the avatar and header images cannot be removed, they can only be
 replaced.`,
 	Example: `  madonctl account update --display-name "Mr President"
   madonctl account update --note "I like madonctl"
-  madonctl account update --avatar happyface.png`,
+  madonctl account update --avatar happyface.png
+  madonctl account update --avatar happyface.png --header banner.jpg
+  madonctl account update --field "Website=https://example.com" --field "Pronouns=they/them"
+  madonctl account update --bot --discoverable=false`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return accountSubcommandsRunE(cmd.Name(), args)
 	},
@@ -384,28 +463,28 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 		return errors.New("too many account identifiers provided")
 	}
 
+	// "-" means the account IDs are read from the standard input
+	if subcmd != "search" && (opt.accountID == "-" || (userInArg && args[0] == "-")) {
+		return accountSubcommandsForEach(subcmd, os.Stdin)
+	}
+
 	if userInArg {
 		// Is the argument an account ID?
 		if _, err := strconv.ParseInt(args[0], 10, 64); err == nil {
 			opt.accountID = args[0]
-		} else if strings.HasPrefix(args[0], "https://") || strings.HasPrefix(args[0], "http://") {
-			// That is not a remote UID scheme
-			opt.accountUID = args[0]
-		} else if subcmd == "follow" {
-			// For the follow API, got to be a remote UID...
-			opt.remoteUID = args[0]
-			// ... unless it's local (i.e. no '@' in the identifier)...
-			fid := strings.TrimLeft(args[0], "@")
-			if !strings.ContainsRune(fid, '@') {
-				opt.accountUID = args[0]
-				opt.remoteUID = ""
-			}
 		} else {
-			// Fall back to account UID
+			// Handle (user@instance) or profile URL
 			opt.accountUID = args[0]
 		}
 	}
 
+	// The --account-id flag also accepts a handle or a profile URL
+	if opt.accountID != "" {
+		if _, err := strconv.ParseInt(opt.accountID, 10, 64); err != nil {
+			opt.accountUID, opt.accountID = opt.accountID, ""
+		}
+	}
+
 	if opt.accountUID != "" {
 		if opt.accountID != "" {
 			return errors.New("cannot use both account ID and UID")
@@ -415,7 +494,7 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 		if err = madonInit(true); err != nil {
 			return err
 		}
-		opt.accountID, err = accountLookupUser(opt.accountUID)
+		opt.accountID, err = resolveAccount(opt.accountUID)
 		if err != nil || opt.accountID == "" {
 			if err != nil {
 				errPrint("Cannot find user '%s': %v", opt.accountUID, err)
@@ -462,7 +541,7 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 				return errors.New("missing account ID")
 			}
 		}
-	case "relationships":
+	case "relationships", "familiar-followers":
 		if opt.accountID == "" && len(opt.accountIDs) == 0 {
 			return errors.New("missing account IDs")
 		}
@@ -552,7 +631,17 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 		obj = accountList
 	case "statuses":
 		var statusList []madon.Status
-		statusList, err = gClient.GetAccountStatuses(opt.accountID, opt.onlyPinned, opt.onlyMedia, opt.excludeReplies, limOpts)
+		filters := madonext.AccountStatusesParams{
+			OnlyPinned:     opt.onlyPinned,
+			OnlyMedia:      opt.onlyMedia,
+			ExcludeReplies: opt.excludeReplies,
+			ExcludeReblogs: opt.excludeReblogs,
+			Tagged:         strings.TrimLeft(opt.tagged, "#"),
+		}
+		statusList, err = gClient.GetAccountStatusesFiltered(opt.accountID, filters, capLimitParams(limOpts))
+		if maxResults > 0 && len(statusList) > int(maxResults) {
+			statusList = statusList[:maxResults]
+		}
 		if opt.keep > 0 && len(statusList) > int(opt.keep) {
 			statusList = statusList[:opt.keep]
 		}
@@ -564,8 +653,20 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 			obj = relationship
 			break
 		}
+		// Set the options that have been explicitly requested
+		var followReblogs, followNotify *bool
+		if accountFollowFlags.Lookup("reblogs").Changed || accountFollowFlags.Lookup("show-reblogs").Changed {
+			followReblogs = &opt.reblogs
+		}
+		if accountFollowFlags.Lookup("notify").Changed {
+			followNotify = &opt.notify
+		}
+
 		if opt.accountID == "" {
 			if opt.remoteUID != "" {
+				if followReblogs != nil || followNotify != nil {
+					return errors.New("--reblogs and --notify cannot be used with --remote")
+				}
 				// Remote account
 				var account *madon.Account
 				account, err = gClient.FollowRemoteAccount(opt.remoteUID)
@@ -576,17 +677,15 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 		}
 
 		// Locally-known account
-		var followReblogs *bool
-		if accountFollowFlags.Lookup("show-reblogs").Changed {
-			// Set followReblogs as it's been explicitly requested
-			followReblogs = &opt.reblogs
-		}
-		relationship, err = gClient.FollowAccount(opt.accountID, followReblogs)
+		relationship, err = gClient.FollowAccountWithOptions(opt.accountID, followReblogs, followNotify)
 		obj = relationship
 	case "follow-requests":
 		if opt.list {
 			var followRequests []madon.Account
-			followRequests, err = gClient.GetAccountFollowRequests(limOpts)
+			followRequests, err = gClient.GetAccountFollowRequests(capLimitParams(limOpts))
+			if maxResults > 0 && len(followRequests) > int(maxResults) {
+				followRequests = followRequests[:maxResults]
+			}
 			if opt.accountID != "" { // Display a specific request
 				var fRequest *madon.Account
 				for _, fr := range followRequests {
@@ -626,7 +725,12 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 			if accountMuteFlags.Lookup("notifications").Changed {
 				muteNotif = &opt.muteNotifications
 			}
-			relationship, err = gClient.MuteAccount(opt.accountID, muteNotif)
+			var duration int
+			duration, err = parseMuteDuration(opt.muteDuration)
+			if err != nil {
+				return err
+			}
+			relationship, err = gClient.MuteAccountWithDuration(opt.accountID, muteNotif, duration)
 		}
 		obj = relationship
 	case "pin", "unpin":
@@ -646,14 +750,20 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 		obj = statusList
 	case "blocks":
 		var accountList []madon.Account
-		accountList, err = gClient.GetBlockedAccounts(limOpts)
+		accountList, err = gClient.GetBlockedAccounts(capLimitParams(limOpts))
+		if maxResults > 0 && len(accountList) > int(maxResults) {
+			accountList = accountList[:maxResults]
+		}
 		if opt.keep > 0 && len(accountList) > int(opt.keep) {
 			accountList = accountList[:opt.keep]
 		}
 		obj = accountList
 	case "mutes":
 		var accountList []madon.Account
-		accountList, err = gClient.GetMutedAccounts(limOpts)
+		accountList, err = gClient.GetMutedAccounts(capLimitParams(limOpts))
+		if maxResults > 0 && len(accountList) > int(maxResults) {
+			accountList = accountList[:maxResults]
+		}
 		if opt.keep > 0 && len(accountList) > int(opt.keep) {
 			accountList = accountList[:opt.keep]
 		}
@@ -665,7 +775,7 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 			accountList = accountList[:opt.keep]
 		}
 		obj = accountList
-	case "relationships":
+	case "relationships", "familiar-followers":
 		var ids []madon.ActivityID
 		ids, err = splitIDs(opt.accountIDs)
 		if err != nil {
@@ -677,6 +787,12 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 		if len(ids) < 1 {
 			return errors.New("missing account IDs")
 		}
+		if subcmd == "familiar-followers" {
+			var ff []madonext.FamiliarFollowers
+			ff, err = gClient.GetFamiliarFollowers(ids)
+			obj = ff
+			break
+		}
 		var relationships []madon.Relationship
 		relationships, err = gClient.GetAccountRelationships(ids)
 		obj = relationships
@@ -716,10 +832,16 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 			change = true
 		}
 		if accountUpdateFlags.Lookup("avatar").Changed {
+			if err := checkImageFile(opt.avatar); err != nil {
+				return errors.Wrap(err, "invalid avatar")
+			}
 			updateParams.AvatarImagePath = &opt.avatar
 			change = true
 		}
 		if accountUpdateFlags.Lookup("header").Changed {
+			if err := checkImageFile(opt.header); err != nil {
+				return errors.Wrap(err, "invalid header")
+			}
 			updateParams.HeaderImagePath = &opt.header
 			change = true
 		}
@@ -752,7 +874,7 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 			source.Sensitive = &opt.defaultSensitive
 			change = true
 		}
-		if accountUpdateFlags.Lookup("profile-field").Changed {
+		if accountUpdateFlags.Lookup("field").Changed {
 			var fa = []madon.Field{}
 			for _, f := range opt.profileFields {
 				kv := strings.SplitN(f, "=", 2)
@@ -765,14 +887,21 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 			change = true
 		}
 
-		if !change { // We want at least one update
+		discoverable := accountUpdateFlags.Lookup("discoverable").Changed
+
+		if !change && !discoverable { // We want at least one update
 			return errors.New("missing parameters")
 		}
 
 		updateParams.Source = source
 
 		var account *madon.Account
-		account, err = gClient.UpdateAccount(updateParams)
+		if change {
+			account, err = gClient.UpdateAccount(updateParams)
+		}
+		if err == nil && discoverable {
+			account, err = gClient.UpdateAccountDiscoverable(opt.discoverable)
+		}
 		obj = account
 	default:
 		return errors.New("accountSubcommand: internal error")
@@ -794,10 +923,51 @@ func accountSubcommandsRunE(subcmd string, args []string) error {
 	return p.printObj(obj)
 }
 
-// accountLookupUser tries to find a (single) user matching 'user'
-// If the user is an HTTP URL, it will use the search API, else
-// it will use the accounts/search API.
-func accountLookupUser(user string) (madon.ActivityID, error) {
+// accountSubcommandsForEach runs the account subcommand for each account ID
+// (or handle) read from r, one per line
+func accountSubcommandsForEach(subcmd string, r io.Reader) error {
+	savedID := accountsOpts.accountID
+	defer func() { accountsOpts.accountID = savedID }()
+
+	var count int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		id := strings.TrimSpace(scanner.Text())
+		if id == "" || id == "-" {
+			continue
+		}
+		accountsOpts.accountID = id
+		if err := accountSubcommandsRunE(subcmd, nil); err != nil {
+			return errors.Wrapf(err, "account '%s'", id)
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "cannot read standard input")
+	}
+	if count == 0 {
+		return errors.New("no account ID read from standard input")
+	}
+	return nil
+}
+
+// accountIDCache contains the account IDs already resolved by resolveAccount
+var accountIDCache = make(map[string]madon.ActivityID)
+
+// resolveAccount returns the account ID matching 'user'
+// The user can be a numeric account ID, a user@instance handle (with or
+// without a leading '@') or a profile URL.  Handles and URLs are looked up
+// with the search API (remote accounts are resolved); local user names are
+// looked up with the accounts/search API.
+// The results are cached for the current invocation.
+func resolveAccount(user string) (madon.ActivityID, error) {
+	if _, err := strconv.ParseInt(user, 10, 64); err == nil {
+		return user, nil
+	}
+	if accID, ok := accountIDCache[user]; ok {
+		return accID, nil
+	}
+
 	var accID madon.ActivityID
 
 	if strings.HasPrefix(user, "https://") || strings.HasPrefix(user, "http://") {
@@ -813,16 +983,26 @@ func accountLookupUser(user string) (madon.ActivityID, error) {
 				accID = res.Accounts[0].ID
 			}
 		}
+	} else if handle := strings.TrimLeft(user, "@"); strings.ContainsRune(handle, '@') {
+		res, err := gClient.Search(handle, true)
+		if err != nil {
+			return "", err
+		}
+		if res != nil {
+			for _, a := range res.Accounts {
+				if a.Acct == handle || accountAddress(&a) == handle {
+					accID = a.ID
+					break
+				}
+			}
+		}
 	} else {
-		// Remove leading '@'
-		user = strings.TrimLeft(user, "@")
-
-		accList, err := gClient.SearchAccounts(user, false, &madon.LimitParams{Limit: 2})
+		accList, err := gClient.SearchAccounts(handle, false, &madon.LimitParams{Limit: 2})
 		if err != nil {
 			return "", err
 		}
 		for _, u := range accList {
-			if u.Acct == user {
+			if u.Acct == handle {
 				accID = u.ID
 				break
 			}
@@ -833,7 +1013,27 @@ func accountLookupUser(user string) (madon.ActivityID, error) {
 		return "", errors.New("user not found")
 	}
 	if verbose {
-		errPrint("User '%s' is account ID %d", user, user)
+		errPrint("User '%s' is account ID %s", user, accID)
 	}
+	accountIDCache[user] = accID
 	return accID, nil
 }
+
+// parseMuteDuration converts a duration (number of seconds or Go duration
+// string) to a number of seconds.  An empty string means 0 (indefinite).
+func parseMuteDuration(d string) (int, error) {
+	if d == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(d); err == nil {
+		if n < 0 {
+			return 0, errors.New("invalid mute duration")
+		}
+		return n, nil
+	}
+	td, err := time.ParseDuration(d)
+	if err != nil || td < 0 {
+		return 0, errors.New("invalid mute duration")
+	}
+	return int(td.Seconds()), nil
+}
@@ -0,0 +1,126 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var announcementsOpts struct {
+	withDismissed bool
+}
+
+// announcementsCmd represents the announcements command
+var announcementsCmd = &cobra.Command{
+	Use:     "announcements",
+	Aliases: []string{"announcement"},
+	Short:   "Display and manage server announcements",
+	RunE:    announcementsListRunE, // Defaults to list
+	Example: `  madonctl announcements list
+  madonctl announcements list --with-dismissed
+  madonctl announcements dismiss 8
+  madonctl announcements react 8 👍
+  madonctl announcements unreact 8 👍`,
+}
+
+func init() {
+	RootCmd.AddCommand(announcementsCmd)
+
+	// Subcommands
+	announcementsCmd.AddCommand(announcementsSubcommands...)
+
+	announcementsListSubcommand.Flags().BoolVar(&announcementsOpts.withDismissed, "with-dismissed", false, "Include dismissed announcements")
+}
+
+var announcementsSubcommands = []*cobra.Command{
+	announcementsListSubcommand,
+	&cobra.Command{
+		Use:   "dismiss ID",
+		Short: "Mark an announcement as read",
+		RunE:  announcementsUpdateRunE,
+	},
+	&cobra.Command{
+		Use:   "react ID EMOJI",
+		Short: "Add a reaction to an announcement",
+		RunE:  announcementsUpdateRunE,
+	},
+	&cobra.Command{
+		Use:   "unreact ID EMOJI",
+		Short: "Remove a reaction from an announcement",
+		RunE:  announcementsUpdateRunE,
+	},
+}
+
+var announcementsListSubcommand = &cobra.Command{
+	Use:     "list",
+	Short:   "Display the announcements (default subcommand)",
+	Aliases: []string{"ls", "get", "display", "show"},
+	RunE:    announcementsListRunE,
+}
+
+func announcementsListRunE(cmd *cobra.Command, args []string) error {
+	opt := announcementsOpts
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	announcements, err := gClient.GetAnnouncements(opt.withDismissed)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(announcements)
+}
+
+func announcementsUpdateRunE(cmd *cobra.Command, args []string) error {
+	subcmd := cmd.Name()
+
+	nArgs := 2
+	if subcmd == "dismiss" {
+		nArgs = 1
+	}
+	if len(args) != nArgs {
+		return errors.Errorf("wrong usage: %s needs %d argument(s)", subcmd, nArgs)
+	}
+	if args[0] == "" {
+		return errors.New("missing announcement ID")
+	}
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	var err error
+
+	switch subcmd {
+	case "dismiss":
+		err = gClient.DismissAnnouncement(args[0])
+	case "react":
+		err = gClient.AddAnnouncementReaction(args[0], args[1])
+	case "unreact":
+		err = gClient.RemoveAnnouncementReaction(args[0], args[1])
+	default:
+		return errors.New("announcementsUpdateRunE: internal error")
+	}
+
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/printer"
+)
+
+var appOpts struct {
+	name, website string
+	save          bool
+}
+
+// appCmd represents the app command
+var appCmd = &cobra.Command{
+	Use:   "app",
+	Short: "Manage the client application",
+}
+
+func init() {
+	RootCmd.AddCommand(appCmd)
+
+	// Subcommands
+	appCmd.AddCommand(appSubcommands...)
+
+	appRegisterSubcommand.Flags().StringVar(&appOpts.name, "app-name", AppName, "Application name")
+	appRegisterSubcommand.Flags().StringVar(&appOpts.website, "website", AppWebsite, "Application website")
+	appRegisterSubcommand.Flags().BoolVar(&appOpts.save, "save", false, "Save the application credentials to the configuration file")
+}
+
+var appSubcommands = []*cobra.Command{
+	appRegisterSubcommand,
+}
+
+var appRegisterSubcommand = &cobra.Command{
+	Use:   "register",
+	Short: "Register a new client application",
+	Long: `Register a new client application
+
+The application ID and secret are displayed; they can be saved to the
+configuration file with the --save flag.`,
+	Example: `  madonctl app register --instance INSTANCE
+  madonctl app register --instance INSTANCE --scopes read --save`,
+	RunE: appRegisterRunE,
+}
+
+const appRegistrationTemplate = `instance: '{{.InstanceURL}}'
+app_id: '{{.ID}}'
+app_secret: '{{.Secret}}'
+`
+
+func appRegisterRunE(cmd *cobra.Command, args []string) error {
+	opt := appOpts
+
+	instance, err := normalizeInstanceURL(viper.GetString("instance"))
+	if err != nil {
+		return err
+	}
+	appScopes, err := parseScopes(viper.GetStringSlice("scopes"))
+	if err != nil {
+		return err
+	}
+
+	mc, err := madon.NewApp(opt.name, opt.website, appScopes, madon.NoRedirect, instance)
+	if err != nil {
+		errPrint("Error: app registration failed: %s", err.Error())
+		os.Exit(1)
+	}
+
+	if opt.save {
+		settings := map[string]interface{}{
+			"instance":   mc.InstanceURL,
+			"app_id":     mc.ID,
+			"app_secret": mc.Secret,
+		}
+		if err := configUpdateFile(settings); err != nil {
+			errPrint("Error: %s", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	var p printer.ResourcePrinter
+	if getOutputFormat() == "plain" {
+		p, err = printer.NewPrinterTemplate(printer.Options{"template": appRegistrationTemplate})
+	} else {
+		p, err = getPrinter()
+	}
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.PrintObj(mc, nil, "")
+}
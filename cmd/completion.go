@@ -10,14 +10,19 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/printer/html2text"
 )
 
 var completionCmd = &cobra.Command{
-	Use:       "completion bash|zsh",
+	Use:       "completion bash|zsh|fish|powershell",
 	Short:     "Generate shell completion",
-	ValidArgs: []string{"bash", "zsh"},
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) < 1 {
 			errPrint("Please specify your shell")
@@ -35,8 +40,18 @@ var completionCmd = &cobra.Command{
 				errPrint("Error: %s", err.Error())
 				os.Exit(1)
 			}
+		case "fish":
+			if err := runCompletionFish(os.Stdout, RootCmd); err != nil {
+				errPrint("Error: %s", err.Error())
+				os.Exit(1)
+			}
+		case "powershell":
+			if err := runCompletionPowerShell(os.Stdout, RootCmd); err != nil {
+				errPrint("Error: %s", err.Error())
+				os.Exit(1)
+			}
 		default:
-			errPrint("Only bash is supported at the moment")
+			errPrint("Unsupported shell: %s (bash, zsh, fish or powershell expected)", args[0])
 			os.Exit(1)
 		}
 	},
@@ -50,6 +65,14 @@ func runCompletionBash(out io.Writer, c *cobra.Command) error {
 	return c.GenBashCompletion(out)
 }
 
+func runCompletionFish(out io.Writer, c *cobra.Command) error {
+	return c.GenFishCompletion(out, true)
+}
+
+func runCompletionPowerShell(out io.Writer, c *cobra.Command) error {
+	return c.GenPowerShellCompletionWithDesc(out)
+}
+
 // Many thanks to the Kubernetes project for this one!
 func runCompletionZsh(out io.Writer, c *cobra.Command) error {
 	const zshInitialization = `# Copyright 2016 The Kubernetes Authors.
@@ -230,3 +253,105 @@ __madonctl_bash_source <(__madonctl_convert_bash_to_zsh)
 	out.Write([]byte(zshTail))
 	return err
 }
+
+// completionTimeout is the API timeout used for dynamic completion
+const completionTimeout = 5 * time.Second
+
+// completionInit sets up the API client for dynamic completion
+// It fails silently, so that completion never blocks.
+// A new application is never registered from completion: app credentials
+// or a user token must be configured.
+func completionInit() bool {
+	hasApp := viper.GetString("app_id") != "" && viper.GetString("app_secret") != ""
+	if !hasApp && viper.GetString("token") == "" {
+		return false
+	}
+	viper.Set("timeout", completionTimeout)
+	return madonInit(true) == nil
+}
+
+// completeStatusIDs returns the IDs of the latest home timeline statuses
+// for shell completion
+func completeStatusIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !completionInit() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sl, err := gClient.GetTimelines("home", false, false, &madon.LimitParams{Limit: 20})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for _, s := range sl {
+		if !strings.HasPrefix(s.ID, toComplete) {
+			continue
+		}
+		desc, err := html2text.Textify(s.Content)
+		if err != nil {
+			desc = s.Content
+		}
+		if s.Account != nil {
+			desc = "@" + s.Account.Acct + ": " + desc
+		}
+		ids = append(ids, s.ID+"\t"+completionDescription(desc))
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAccountIDs returns the IDs of the accounts followed by the user
+// for shell completion
+func completeAccountIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !completionInit() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	account, err := gClient.GetCurrentAccount()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	al, err := gClient.GetAccountFollowing(account.ID, &madon.LimitParams{Limit: 40})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for _, a := range al {
+		if strings.HasPrefix(a.ID, toComplete) {
+			ids = append(ids, a.ID+"\t@"+a.Acct)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAccountIDArg completes the optional account argument
+func completeAccountIDArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeAccountIDs(cmd, args, toComplete)
+}
+
+// completionDescription returns a one-line, shortened description
+func completionDescription(s string) string {
+	const maxLen = 60
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxLen {
+		s = string(r[:maxLen-1]) + "…"
+	}
+	return s
+}
+
+// Flag value completion functions
+// They are used by all shells (through the hidden __complete command).
+var (
+	completeOutputFormats = cobra.FixedCompletions([]string{"plain", "json", "yaml", "template", "theme", "rss"}, cobra.ShellCompDirectiveNoFileComp)
+	completeColorModes    = cobra.FixedCompletions([]string{"auto", "on", "off"}, cobra.ShellCompDirectiveNoFileComp)
+	completeVisibility    = cobra.FixedCompletions([]string{"direct", "private", "unlisted", "public"}, cobra.ShellCompDirectiveNoFileComp)
+	completeBoostVis      = cobra.FixedCompletions([]string{"private", "unlisted", "public"}, cobra.ShellCompDirectiveNoFileComp)
+)
+
+// completeThemes returns the list of available themes
+func completeThemes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	themes, err := getThemes()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return themes, cobra.ShellCompDirectiveNoFileComp
+}
@@ -6,9 +6,16 @@
 package cmd
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/ghodss/yaml"
 	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
@@ -26,6 +33,8 @@ initial configuration file (see the examples below).
 This command is disabled if the safe_mode setting is set to true in the
 configuration file.`,
 	Example: `  madonctl config dump -i INSTANCE -L USERNAME -P PASS > config.yaml
+  madonctl config edit
+  madonctl config validate
   madonctl whoami
   madonctl whoami --template '{{.access_token}}'`,
 }
@@ -35,6 +44,12 @@ func init() {
 
 	// Subcommands
 	configCmd.AddCommand(configSubcommands...)
+
+	configWhoamiSubcommand.Flags().BoolVar(&configOpts.stats, "stats", false, "Display the account statistics")
+}
+
+var configOpts struct {
+	stats bool // For whoami
 }
 
 var configSubcommands = []*cobra.Command{
@@ -46,12 +61,32 @@ var configSubcommands = []*cobra.Command{
 			return configDump(false)
 		},
 	},
+	configWhoamiSubcommand,
+	configEditSubcommand,
+	&cobra.Command{
+		Use:     "validate",
+		Aliases: []string{"check"},
+		Short:   "Check the configuration and credentials",
+		Long: `Check the configuration and credentials
+
+The command signs in and fetches the current account to check the
+credentials are valid.  It exits with a non-zero status code on failure.
+No data is modified.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return configValidate()
+		},
+	},
 	&cobra.Command{
-		Use:     "whoami",
-		Aliases: []string{"token"},
-		Short:   "Display user token",
+		Use:   "presets",
+		Short: "Display available template presets",
+		Long: `Display available template presets
+
+The template presets are bundled with madonctl and can be used with the
+--template-preset option.`,
+		Example: `  madonctl config presets
+  madonctl timeline --template-preset status-short`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return configDisplayToken()
+			return configDisplayPresets()
 		},
 	},
 	&cobra.Command{
@@ -64,6 +99,32 @@ var configSubcommands = []*cobra.Command{
 	},
 }
 
+var configEditSubcommand = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the configuration file",
+	Long: `Edit the configuration file
+
+The configuration file is opened with $VISUAL or $EDITOR (defaults to vi).
+It is created if it does not exist, and it is checked when the editor exits.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return configEdit()
+	},
+}
+
+var configWhoamiSubcommand = &cobra.Command{
+	Use:     "whoami",
+	Aliases: []string{"token"},
+	Short:   "Display user token",
+	Long: `Display user token
+
+With --stats, the account statistics (statuses, followers and following
+counts, last status date) are displayed as well.`,
+	Example: `  madonctl config whoami --stats`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return configDisplayToken()
+	},
+}
+
 const configurationTemplate = `---
 instance: '{{.InstanceURL}}'
 app_id: '{{.ID}}'
@@ -144,7 +205,179 @@ func configDisplayToken() error {
 		errPrint("Error: %v", err)
 		os.Exit(1)
 	}
-	return p.printObj(gClient.UserToken)
+	// With --stats, two objects are displayed; with the YAML output they
+	// are printed as separate documents.
+	yamlDocs := configOpts.stats && getOutputFormat() == "yaml"
+
+	if yamlDocs {
+		fmt.Print("---\n")
+	}
+	if err := p.printObj(gClient.UserToken); err != nil {
+		return err
+	}
+	if !configOpts.stats {
+		return nil
+	}
+
+	stats, err := gClient.GetCurrentAccountStats()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	if yamlDocs {
+		fmt.Print("---\n")
+	}
+	return p.printObj(stats)
+}
+
+// configFilePath returns the path to the active configuration file
+func configFilePath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	if cfile := viper.ConfigFileUsed(); cfile != "" {
+		return cfile
+	}
+	return os.ExpandEnv(defaultConfigFile)
+}
+
+// configFileOptional returns true if the command being run can create the
+// configuration file (or fix a broken one, for "config edit").
+func configFileOptional() bool {
+	c, _, err := RootCmd.Find(os.Args[1:])
+	if err != nil {
+		return false
+	}
+	switch c {
+	case configEditSubcommand:
+		return true
+	case loginCmd, appRegisterSubcommand:
+		return !fileExists(cfgFile)
+	}
+	return false
+}
+
+// configEdit opens the configuration file with the user's editor
+func configEdit() error {
+	cfile := configFilePath()
+	if cfile == "/dev/null" {
+		return errors.New("no configuration file")
+	}
+
+	if !fileExists(cfile) {
+		if err := os.MkdirAll(filepath.Dir(cfile), 0700); err != nil {
+			return errors.Wrap(err, "cannot create configuration directory")
+		}
+		if err := ioutil.WriteFile(cfile, []byte("---\n"), 0600); err != nil {
+			return errors.Wrap(err, "cannot create configuration file")
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	ea := strings.Fields(editor)
+	cmd := exec.Command(ea[0], append(ea[1:], cfile)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "editor failed")
+	}
+
+	// Check the file can still be parsed
+	data, err := ioutil.ReadFile(cfile)
+	if err != nil {
+		return errors.Wrap(err, "cannot read configuration file")
+	}
+	var conf map[string]interface{}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		errPrint("Error: invalid configuration file '%s': %v", cfile, err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+// configUpdateFile sets the given settings in the configuration file.
+// The file is created if it does not exist.
+// Note: comments are not preserved.
+func configUpdateFile(settings map[string]interface{}) error {
+	cfile := configFilePath()
+	if cfile == "/dev/null" {
+		return errors.New("no configuration file")
+	}
+
+	conf := make(map[string]interface{})
+	if fileExists(cfile) {
+		data, err := ioutil.ReadFile(cfile)
+		if err != nil {
+			return errors.Wrap(err, "cannot read configuration file")
+		}
+		if err := yaml.Unmarshal(data, &conf); err != nil {
+			return errors.Wrap(err, "cannot parse configuration file")
+		}
+		if conf == nil {
+			conf = make(map[string]interface{})
+		}
+	} else if err := os.MkdirAll(filepath.Dir(cfile), 0700); err != nil {
+		return errors.Wrap(err, "cannot create configuration directory")
+	}
+
+	for k, v := range settings {
+		conf[k] = v
+	}
+
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(cfile, append([]byte("---\n"), data...), 0600); err != nil {
+		return errors.Wrap(err, "cannot write configuration file")
+	}
+	if verbose {
+		errPrint("Configuration file '%s' updated", cfile)
+	}
+	return nil
+}
+
+// configValidate checks the user can log in with the current configuration
+func configValidate() error {
+	if err := madonInit(true); err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+
+	account, err := gClient.GetCurrentAccount()
+	if err != nil {
+		errPrint("Error: cannot verify credentials: %s", err.Error())
+		os.Exit(1)
+	}
+	fmt.Printf("OK: logged in as @%s\n", account.Acct)
+	return nil
+}
+
+// configDisplayPresets lists the available template presets
+func configDisplayPresets() error {
+	var p printer.ResourcePrinter
+	var err error
+
+	presets := templatePresetNames()
+
+	if getOutputFormat() == "plain" {
+		pOptions := printer.Options{"template": `{{printf "%s\n" .}}`}
+		p, err = printer.NewPrinterTemplate(pOptions)
+	} else {
+		p, err = getPrinter()
+	}
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.PrintObj(presets, nil, "")
 }
 
 // configDisplayThemes lists the available themes
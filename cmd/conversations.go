@@ -0,0 +1,115 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+)
+
+var conversationsOpts struct {
+	// Used to limit the number of results
+	limit, keep uint
+	all         bool
+}
+
+// conversationsCmd represents the conversations command
+var conversationsCmd = &cobra.Command{
+	Use:     "conversations",
+	Aliases: []string{"conversation", "conv"},
+	Short:   "Display and remove direct conversations",
+	RunE:    conversationsGetRunE, // Defaults to list
+	Example: `  madonctl conversations
+  madonctl conversations list --limit 5
+  madonctl conversations remove 42`,
+}
+
+func init() {
+	RootCmd.AddCommand(conversationsCmd)
+
+	// Subcommands
+	conversationsCmd.AddCommand(conversationsSubcommands...)
+
+	conversationsCmd.PersistentFlags().UintVarP(&conversationsOpts.limit, "limit", "l", 0, "Limit number of API results")
+	conversationsCmd.PersistentFlags().UintVarP(&conversationsOpts.keep, "keep", "k", 0, "Limit number of results")
+	conversationsCmd.PersistentFlags().BoolVar(&conversationsOpts.all, "all", false, "Fetch all results")
+}
+
+var conversationsSubcommands = []*cobra.Command{
+	conversationsGetSubcommand,
+	conversationsRemoveSubcommand,
+}
+
+var conversationsGetSubcommand = &cobra.Command{
+	Use:     "list",
+	Short:   "Display the conversations (default subcommand)",
+	Aliases: []string{"ls", "get", "display", "show"},
+	RunE:    conversationsGetRunE,
+}
+
+var conversationsRemoveSubcommand = &cobra.Command{
+	Use:     "remove ID",
+	Short:   "Remove a conversation",
+	Aliases: []string{"delete", "del", "rm"},
+	RunE:    conversationsRemoveRunE,
+}
+
+func conversationsGetRunE(cmd *cobra.Command, args []string) error {
+	opt := conversationsOpts
+
+	// Set up LimitParams
+	var limOpts *madon.LimitParams
+	if opt.all || opt.limit > 0 {
+		limOpts = new(madon.LimitParams)
+		limOpts.All = opt.all
+	}
+	if opt.limit > 0 {
+		limOpts.Limit = int(opt.limit)
+	}
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	conversations, err := gClient.GetConversations(limOpts)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	if opt.keep > 0 && len(conversations) > int(opt.keep) {
+		conversations = conversations[:opt.keep]
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(conversations)
+}
+
+func conversationsRemoveRunE(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 || args[0] == "" {
+		return errors.New("missing conversation ID")
+	}
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	if err := gClient.DeleteConversation(args[0]); err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var directoryOpts struct {
+	order         string
+	local         bool
+	limit, offset uint
+}
+
+// directoryCmd represents the directory command
+var directoryCmd = &cobra.Command{
+	Use:   "directory [--order active|new] [--local]",
+	Short: "Browse the profile directory",
+	Long: `
+The directory command lists accounts from the instance profile directory.
+Only accounts that have opted in to be listed in the directory are returned.`,
+	Example: `  madonctl directory
+  madonctl directory --local --order new
+  madonctl directory --limit 40 --offset 40`,
+	RunE: directoryRunE,
+}
+
+func init() {
+	RootCmd.AddCommand(directoryCmd)
+
+	directoryCmd.Flags().StringVar(&directoryOpts.order, "order", "", "Sort order (active|new)")
+	directoryCmd.Flags().BoolVar(&directoryOpts.local, "local", false, "Only accounts from the local instance")
+	directoryCmd.Flags().UintVarP(&directoryOpts.limit, "limit", "l", 0, "Limit number of API results")
+	directoryCmd.Flags().UintVar(&directoryOpts.offset, "offset", 0, "Skip the first results")
+}
+
+func directoryRunE(cmd *cobra.Command, args []string) error {
+	opt := directoryOpts
+
+	switch opt.order {
+	case "", "active", "new":
+	default:
+		return errors.Errorf("invalid order value '%s'", opt.order)
+	}
+
+	// We don't have to log in
+	if err := madonInit(false); err != nil {
+		return err
+	}
+
+	accountList, err := gClient.GetAccountDirectory(opt.order, opt.local, int(opt.offset), int(opt.limit))
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return p.printObj(accountList)
+}
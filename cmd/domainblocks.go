@@ -17,18 +17,22 @@ import (
 var domainBlocksOpts struct {
 	show, block, unblock bool
 
-	limit          uint              // Limit the results
+	limit, keep    uint             // Limit the results
 	sinceID, maxID madon.ActivityID // Query boundaries
-	all            bool              // Try to fetch all results
+	all            bool             // Try to fetch all results
 }
 
-// timelinesCmd represents the timelines command
+// domainBlocksCmd represents the domain-blocks command
 var domainBlocksCmd = &cobra.Command{
 	Use:     "domain-blocks --show|--block|--unblock [DOMAINNAME]",
 	Aliases: []string{"domain-block"},
 	Short:   "Display, add or remove user-blocked domains",
 	RunE:    domainBlocksRunE,
-	Example: `  madonctl domain-blocks --show
+	Example: `  madonctl domain-blocks list
+  madonctl domain-blocks block example.com
+  madonctl domain-blocks unblock example.com
+
+  madonctl domain-blocks --show
   madonctl domain-blocks --block   example.com
   madonctl domain-blocks --unblock example.com`,
 }
@@ -36,19 +40,47 @@ var domainBlocksCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(domainBlocksCmd)
 
+	// Subcommands
+	domainBlocksCmd.AddCommand(domainBlocksSubcommands...)
+
 	domainBlocksCmd.Flags().BoolVar(&domainBlocksOpts.show, "show", false, "List current user-blocked domains")
 	domainBlocksCmd.Flags().BoolVar(&domainBlocksOpts.block, "block", false, "Block domain")
 	domainBlocksCmd.Flags().BoolVar(&domainBlocksOpts.unblock, "unblock", false, "Unblock domain")
 
-	domainBlocksCmd.Flags().UintVarP(&domainBlocksOpts.limit, "limit", "l", 0, "Limit number of results")
-	domainBlocksCmd.Flags().StringVar(&domainBlocksOpts.sinceID, "since-id", "", "Request IDs greater than a value")
-	domainBlocksCmd.Flags().StringVar(&domainBlocksOpts.maxID, "max-id", "", "Request IDs less (or equal) than a value")
-	domainBlocksCmd.Flags().BoolVar(&domainBlocksOpts.all, "all", false, "Fetch all results")
+	domainBlocksCmd.PersistentFlags().UintVarP(&domainBlocksOpts.limit, "limit", "l", 0, "Limit number of API results")
+	domainBlocksCmd.PersistentFlags().UintVarP(&domainBlocksOpts.keep, "keep", "k", 0, "Limit number of results")
+	domainBlocksCmd.PersistentFlags().StringVar(&domainBlocksOpts.sinceID, "since-id", "", "Request IDs greater than a value")
+	domainBlocksCmd.PersistentFlags().StringVar(&domainBlocksOpts.maxID, "max-id", "", "Request IDs less (or equal) than a value")
+	domainBlocksCmd.PersistentFlags().BoolVar(&domainBlocksOpts.all, "all", false, "Fetch all results")
+}
+
+var domainBlocksSubcommands = []*cobra.Command{
+	&cobra.Command{
+		Use:     "list",
+		Short:   "List current user-blocked domains",
+		Aliases: []string{"ls", "show", "display"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return domainBlocksAction("list", args)
+		},
+	},
+	&cobra.Command{
+		Use:   "block DOMAINNAME",
+		Short: "Block a domain",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return domainBlocksAction("block", args)
+		},
+	},
+	&cobra.Command{
+		Use:   "unblock DOMAINNAME",
+		Short: "Unblock a domain",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return domainBlocksAction("unblock", args)
+		},
+	},
 }
 
 func domainBlocksRunE(cmd *cobra.Command, args []string) error {
 	opt := domainBlocksOpts
-	var domName madon.DomainName
 
 	// Check flags
 	if opt.block && opt.unblock {
@@ -59,16 +91,31 @@ func domainBlocksRunE(cmd *cobra.Command, args []string) error {
 		if opt.show {
 			return errors.New("cannot use both --[un]block and --show")
 		}
+	}
+
+	switch {
+	case opt.show:
+		return domainBlocksAction("list", args)
+	case opt.block:
+		return domainBlocksAction("block", args)
+	case opt.unblock:
+		return domainBlocksAction("unblock", args)
+	}
+	return errors.New("missing flag: please provide --show, --block or --unblock")
+}
+
+// domainBlocksAction lists, blocks or unblocks domains
+func domainBlocksAction(action string, args []string) error {
+	opt := domainBlocksOpts
+	var domName madon.DomainName
+
+	if action == "block" || action == "unblock" {
 		if len(args) != 1 {
 			return errors.New("missing domain name")
 		}
 		domName = madon.DomainName(args[0])
 	}
 
-	if !opt.show && !opt.block && !opt.unblock {
-		return errors.New("missing flag: please provide --show, --block or --unblock")
-	}
-
 	// Set up LimitParams
 	var limOpts *madon.LimitParams
 	if opt.all || opt.limit > 0 || opt.sinceID != "" || opt.maxID != "" {
@@ -93,17 +140,20 @@ func domainBlocksRunE(cmd *cobra.Command, args []string) error {
 	var obj interface{}
 	var err error
 
-	switch {
-	case opt.show:
+	switch action {
+	case "list":
 		var domainList []madon.DomainName
 		domainList, err = gClient.GetBlockedDomains(limOpts)
+		if opt.keep > 0 && len(domainList) > int(opt.keep) {
+			domainList = domainList[:opt.keep]
+		}
 		obj = domainList
-	case opt.block:
+	case "block":
 		err = gClient.BlockDomain(domName)
-	case opt.unblock:
+	case "unblock":
 		err = gClient.UnblockDomain(domName)
 	default:
-		return errors.New("domainBlocksCmd: internal error")
+		return errors.New("domainBlocksAction: internal error")
 	}
 
 	if err != nil {
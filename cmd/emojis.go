@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/printer"
 )
 
 var emojiOpts struct {
@@ -18,6 +19,8 @@ var emojiOpts struct {
 	limit, keep uint
 	//sinceID, maxID int64
 	all bool
+
+	shortcodes bool // Only display the shortcodes
 }
 
 // emojiCmd represents the emoji command
@@ -26,6 +29,8 @@ var emojiCmd = &cobra.Command{
 	Aliases: []string{"emoji"},
 	Short:   "Display server emojis",
 	RunE:    emojiGetRunE, // Defaults to list
+	Example: `  madonctl emojis list --all
+  madonctl emojis list --all --shortcodes`,
 }
 
 func init() {
@@ -37,6 +42,7 @@ func init() {
 	emojiGetCustomSubcommand.Flags().UintVarP(&emojiOpts.limit, "limit", "l", 0, "Limit number of API results")
 	emojiGetCustomSubcommand.Flags().UintVarP(&emojiOpts.keep, "keep", "k", 0, "Limit number of results")
 	emojiGetCustomSubcommand.Flags().BoolVar(&emojiOpts.all, "all", false, "Fetch all results")
+	emojiGetCustomSubcommand.Flags().BoolVar(&emojiOpts.shortcodes, "shortcodes", false, "Only display the shortcodes (e.g. for shell completion)")
 }
 
 var emojiSubcommands = []*cobra.Command{
@@ -44,9 +50,12 @@ var emojiSubcommands = []*cobra.Command{
 }
 
 var emojiGetCustomSubcommand = &cobra.Command{
-	Use:     "list",
-	Short:   "Display the custom emojis (default subcommand)",
-	Long:    `Display the list of custom emojis of the instance.`,
+	Use:   "list",
+	Short: "Display the custom emojis (default subcommand)",
+	Long: `Display the list of custom emojis of the instance.
+
+With --shortcodes, only the :shortcode: list is displayed; it is used by the
+shell completion.`,
 	Aliases: []string{"get", "display", "show"},
 	RunE:    emojiGetRunE,
 }
@@ -89,6 +98,25 @@ func emojiGetRunE(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if opt.shortcodes {
+		var codes []string
+		for _, e := range emojiList {
+			codes = append(codes, ":"+e.ShortCode+":")
+		}
+		obj = codes
+	}
+
+	if opt.shortcodes && getOutputFormat() == "plain" {
+		// One shortcode per line
+		pOptions := printer.Options{"template": `{{printf "%s\n" .}}`}
+		tp, err := printer.NewPrinterTemplate(pOptions)
+		if err != nil {
+			errPrint("Error: %v", err)
+			os.Exit(1)
+		}
+		return tp.PrintObj(obj, nil, "")
+	}
+
 	p, err := getPrinter()
 	if err != nil {
 		errPrint("Error: %v", err)
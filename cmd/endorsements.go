@@ -0,0 +1,151 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+)
+
+var endorsementsOpts struct {
+	accountID madon.ActivityID
+
+	// Used to limit the number of results
+	limit, keep uint
+	all         bool
+}
+
+// endorsementsCmd represents the endorsements command
+var endorsementsCmd = &cobra.Command{
+	Use:     "endorsements",
+	Aliases: []string{"endorsement", "featured"},
+	Short:   "Manage the accounts endorsed on the user profile",
+	Long: `Manage the accounts endorsed on the user profile
+
+See also the "account pin" and "account unpin" commands.`,
+	RunE: endorsementsListRunE, // Defaults to list
+	Example: `  madonctl endorsements list
+  madonctl endorsements add --account-id 1234
+  madonctl endorsements remove --account-id Gargron@mastodon.social`,
+}
+
+func init() {
+	RootCmd.AddCommand(endorsementsCmd)
+
+	// Subcommands
+	endorsementsCmd.AddCommand(endorsementsSubcommands...)
+
+	endorsementsCmd.PersistentFlags().UintVarP(&endorsementsOpts.limit, "limit", "l", 0, "Limit number of API results")
+	endorsementsCmd.PersistentFlags().UintVarP(&endorsementsOpts.keep, "keep", "k", 0, "Limit number of results")
+	endorsementsCmd.PersistentFlags().BoolVar(&endorsementsOpts.all, "all", false, "Fetch all results")
+
+	for _, c := range endorsementsSubcommands[1:] {
+		c.Flags().StringVarP(&endorsementsOpts.accountID, "account-id", "a", "", "Account ID number")
+	}
+}
+
+var endorsementsSubcommands = []*cobra.Command{
+	&cobra.Command{
+		Use:     "list",
+		Short:   "Display the endorsed accounts (default subcommand)",
+		Aliases: []string{"ls", "get", "display", "show"},
+		RunE:    endorsementsListRunE,
+	},
+	&cobra.Command{
+		Use:     "add --account-id ID",
+		Short:   "Endorse an account",
+		Aliases: []string{"pin"},
+		RunE:    endorsementsAddRemoveRunE,
+	},
+	&cobra.Command{
+		Use:     "remove --account-id ID",
+		Short:   "Stop endorsing an account",
+		Aliases: []string{"unpin", "rm", "del"},
+		RunE:    endorsementsAddRemoveRunE,
+	},
+}
+
+func endorsementsListRunE(cmd *cobra.Command, args []string) error {
+	opt := endorsementsOpts
+
+	// Set up LimitParams
+	var limOpts *madon.LimitParams
+	if opt.all || opt.limit > 0 {
+		limOpts = new(madon.LimitParams)
+		limOpts.All = opt.all
+	}
+	if opt.limit > 0 {
+		limOpts.Limit = int(opt.limit)
+	}
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	accountList, err := gClient.GetEndorsements(limOpts)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	if opt.keep > 0 && len(accountList) > int(opt.keep) {
+		accountList = accountList[:opt.keep]
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(accountList)
+}
+
+func endorsementsAddRemoveRunE(cmd *cobra.Command, args []string) error {
+	opt := endorsementsOpts
+
+	if opt.accountID == "" {
+		return errors.New("missing account ID")
+	}
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	accountID, err := resolveAccount(opt.accountID)
+	if err != nil {
+		errPrint("Cannot find user '%s': %v", opt.accountID, err)
+		os.Exit(1)
+	}
+
+	var relationship *madon.Relationship
+
+	switch cmd.Name() {
+	case "add":
+		relationship, err = gClient.PinAccount(accountID)
+	case "remove":
+		relationship, err = gClient.UnpinAccount(accountID)
+	default:
+		return errors.New("endorsementsAddRemoveRunE: internal error")
+	}
+
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(relationship)
+}
@@ -0,0 +1,130 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var featuredTagsOpts struct {
+	suggestions bool
+}
+
+// featuredTagsCmd represents the featured-tags command
+var featuredTagsCmd = &cobra.Command{
+	Use:     "featured-tags",
+	Aliases: []string{"featured-tag", "ftags"},
+	Short:   "Manage the hashtags featured on the user profile",
+	RunE:    featuredTagsListRunE, // Defaults to list
+	Example: `  madonctl featured-tags list
+  madonctl featured-tags list --suggestions
+  madonctl featured-tags add golang
+  madonctl featured-tags remove 42`,
+}
+
+func init() {
+	RootCmd.AddCommand(featuredTagsCmd)
+
+	// Subcommands
+	featuredTagsCmd.AddCommand(featuredTagsSubcommands...)
+
+	featuredTagsListSubcommand.Flags().BoolVar(&featuredTagsOpts.suggestions, "suggestions", false, "Display suggested tags (most used tags)")
+}
+
+var featuredTagsSubcommands = []*cobra.Command{
+	featuredTagsListSubcommand,
+	&cobra.Command{
+		Use:   "add TAG",
+		Short: "Feature a hashtag on the profile",
+		RunE:  featuredTagsAddRemoveRunE,
+	},
+	&cobra.Command{
+		Use:     "remove ID",
+		Short:   "Stop featuring a hashtag",
+		Aliases: []string{"delete", "del", "rm"},
+		RunE:    featuredTagsAddRemoveRunE,
+	},
+}
+
+var featuredTagsListSubcommand = &cobra.Command{
+	Use:     "list",
+	Short:   "Display the featured tags (default subcommand)",
+	Aliases: []string{"ls", "get", "display", "show"},
+	RunE:    featuredTagsListRunE,
+}
+
+func featuredTagsListRunE(cmd *cobra.Command, args []string) error {
+	opt := featuredTagsOpts
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	var obj interface{}
+	var err error
+
+	if opt.suggestions {
+		obj, err = gClient.GetFeaturedTagSuggestions()
+	} else {
+		obj, err = gClient.GetFeaturedTags()
+	}
+
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(obj)
+}
+
+func featuredTagsAddRemoveRunE(cmd *cobra.Command, args []string) error {
+	subcmd := cmd.Name()
+
+	if len(args) != 1 || args[0] == "" {
+		return errors.Errorf("wrong usage: %s needs 1 argument", subcmd)
+	}
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	var obj interface{}
+	var err error
+
+	switch subcmd {
+	case "add":
+		obj, err = gClient.AddFeaturedTag(args[0])
+	case "remove":
+		err = gClient.RemoveFeaturedTag(args[0])
+	default:
+		return errors.New("featuredTagsAddRemoveRunE: internal error")
+	}
+
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	if obj == nil {
+		return nil
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(obj)
+}
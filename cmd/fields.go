@@ -0,0 +1,211 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+	"github.com/McKael/madonctl/printer"
+)
+
+// fieldTypes contains the entity types supported by the fields command
+var fieldTypes = map[string]interface{}{
+	"account":      madon.Account{},
+	"announcement": madonext.Announcement{},
+	"attachment":   madon.Attachment{},
+	"card":         madon.Card{},
+	"context":      madon.Context{},
+	"conversation": madonext.Conversation{},
+	"emoji":        madon.Emoji{},
+	"instance":     madon.Instance{},
+	"list":         madonext.List{},
+	"mention":      madon.Mention{},
+	"notification": madon.Notification{},
+	"poll":         madonext.Poll{},
+	"preferences":  madonext.Preferences{},
+	"relationship": madon.Relationship{},
+	"report":       madon.Report{},
+	"results":      madon.Results{},
+	"rule":         madonext.Rule{},
+	"status":       madon.Status{},
+	"tag":          madonext.Tag{},
+}
+
+// fieldInfo describes an entity field
+type fieldInfo struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+var fieldsCmd = &cobra.Command{
+	Use:   "fields TYPE",
+	Short: "Display the fields of an API entity",
+	Long: `Display the fields of an API entity
+
+This command lists the fields that can be used in templates (and with the
+--fields option) for the given entity type.
+Nested fields are displayed with their full path; the elements of a list
+are noted with "[]" (use the 'range' template action to access them).`,
+	Example: `  madonctl fields status
+  madonctl fields notification -o json`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return fieldTypeNames(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: fieldsRunE,
+}
+
+func init() {
+	RootCmd.AddCommand(fieldsCmd)
+}
+
+func fieldsRunE(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.Errorf("wrong usage: fields needs 1 argument (%s)",
+			strings.Join(fieldTypeNames(), ", "))
+	}
+	obj, ok := fieldTypes[strings.ToLower(args[0])]
+	if !ok {
+		return errors.Errorf("unknown type '%s' (%s)", args[0],
+			strings.Join(fieldTypeNames(), ", "))
+	}
+
+	fields := listFields(reflect.TypeOf(obj), "", nil)
+
+	var p printer.ResourcePrinter
+	var err error
+	of := getOutputFormat()
+	if of == "plain" {
+		pOptions := printer.Options{"template": `{{printf "%-40s %s\n" .path .type}}`}
+		p, err = printer.NewPrinterTemplate(pOptions)
+		of = "template"
+	} else {
+		p, err = getPrinter()
+	}
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+
+	if of == "template" {
+		// The template printer does not know the fieldInfo list type
+		for _, f := range fields {
+			if err := p.PrintObj(f, nil, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return p.PrintObj(fields, nil, "")
+}
+
+// fieldTypeNames returns the sorted list of supported entity types
+func fieldTypeNames() []string {
+	var names []string
+	for n := range fieldTypes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// listFields returns the JSON field paths of the struct type t.
+// The parents argument contains the types being visited, to stop the
+// recursion for self-referencing types (e.g. a status reblog).
+func listFields(t reflect.Type, prefix string, parents []reflect.Type) []fieldInfo {
+	var fields []fieldInfo
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // Unexported field
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			// Embedded struct: its fields are promoted
+			fields = append(fields, listFields(ft, prefix, append(parents, t))...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		path := prefix + name
+
+		fields = append(fields, fieldInfo{Path: path, Type: fieldTypeName(ft)})
+
+		// Nested objects
+		elem := ft
+		if ft.Kind() == reflect.Slice {
+			elem = ft.Elem()
+			for elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			path += "[]"
+		}
+		if elem.Kind() != reflect.Struct || isDateType(elem) || typeIn(elem, append(parents, t)) {
+			continue
+		}
+		fields = append(fields, listFields(elem, path+".", append(parents, t))...)
+	}
+	return fields
+}
+
+// fieldTypeName returns a short description of the type t
+func fieldTypeName(t reflect.Type) string {
+	switch {
+	case isDateType(t):
+		return "date"
+	case t.Kind() == reflect.Slice:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		return "list of " + fieldTypeName(elem)
+	case t.Kind() == reflect.Struct, t.Kind() == reflect.Map:
+		return "object"
+	case t.Kind() == reflect.Bool:
+		return "boolean"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return "integer"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return "number"
+	case t.Kind() == reflect.String:
+		return "string"
+	}
+	return t.Kind().String()
+}
+
+func isDateType(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(madon.MastodonDate{})
+}
+
+func typeIn(t reflect.Type, list []reflect.Type) bool {
+	for _, e := range list {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
@@ -9,16 +9,29 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/McKael/madonctl/madonext"
 )
 
-// timelinesCmd represents the timelines command
+var instanceOpts struct {
+	keep uint
+	v2   bool
+}
+
+// instanceCmd represents the instance command
 var instanceCmd = &cobra.Command{
 	Use:   "instance",
 	Short: "Display current instance information",
 	Long: `Display instance information
 
 This command display the instance information returned by the server.
+
+With --v2, the information is fetched with the v2 API (Mastodon 4.0+), which
+provides more details (e.g. the registration settings).  If the server does
+not support it, the v1 API is used.
 `,
+	Example: `  madonctl instance
+  madonctl instance --v2 --instance mastodon.social`,
 	RunE: instanceRunE,
 }
 
@@ -27,18 +40,46 @@ func init() {
 
 	instanceCmd.AddCommand(instancePeersSubcommand)
 	instanceCmd.AddCommand(instanceActivitySubcommand)
+	instanceCmd.AddCommand(instanceRulesSubcommand)
+
+	instanceCmd.Flags().BoolVar(&instanceOpts.v2, "v2", false, "Use the v2 API (extended information)")
+
+	instancePeersSubcommand.Flags().UintVarP(&instanceOpts.keep, "keep", "k", 0, "Limit number of results")
+	instanceActivitySubcommand.Flags().UintVarP(&instanceOpts.keep, "keep", "k", 0, "Limit number of results (keep the most recent weeks)")
 }
 
 var instancePeersSubcommand = &cobra.Command{
 	Use:   "peers",
 	Short: "Display the instance peers",
-	RunE:  instanceStatsRunE,
+	Long: `Display the instance peers
+
+This command displays the list of domain names of the instances known by the
+server.  The endpoint is public but can be disabled by the administrators.`,
+	Example: `  madonctl instance peers --instance mastodon.social --keep 100`,
+	RunE:    instanceStatsRunE,
 }
 
 var instanceActivitySubcommand = &cobra.Command{
 	Use:   "activity",
 	Short: "Display the instance activity",
-	RunE:  instanceStatsRunE,
+	Long: `Display the instance activity
+
+This command displays the weekly activity statistics of the instance (number
+of statuses, logins and registrations), starting with the current week.
+The endpoint is public but can be disabled by the administrators.`,
+	Example: `  madonctl instance activity --instance mastodon.social --keep 4`,
+	RunE:    instanceStatsRunE,
+}
+
+var instanceRulesSubcommand = &cobra.Command{
+	Use:   "rules",
+	Short: "Display the instance rules",
+	Long: `Display the instance rules
+
+This command displays the numbered rules of the server, which can be
+useful before filing a report.  No login is required.`,
+	Example: `  madonctl instance rules --instance mastodon.social`,
+	RunE:    instanceStatsRunE,
 }
 
 func instanceRunE(cmd *cobra.Command, args []string) error {
@@ -46,8 +87,19 @@ func instanceRunE(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	var i interface{}
+	var err error
+
 	// Get current instance data through the API
-	i, err := gClient.GetCurrentInstance()
+	if instanceOpts.v2 {
+		i, err = gClient.GetInstanceV2()
+		if madonext.IsNotFound(err) {
+			errPrint("Notice: the v2 API is not supported by the server, using v1")
+			i, err = gClient.GetCurrentInstance()
+		}
+	} else {
+		i, err = gClient.GetCurrentInstance()
+	}
 	if err != nil {
 		errPrint("Error: %s", err.Error())
 		os.Exit(1)
@@ -77,6 +129,9 @@ func instanceStatsRunE(cmd *cobra.Command, args []string) error {
 			errPrint("Error: %s", err.Error())
 			os.Exit(1)
 		}
+		if instanceOpts.keep > 0 && len(peers) > int(instanceOpts.keep) {
+			peers = peers[:instanceOpts.keep]
+		}
 		obj = peers
 	case "activity":
 		// Get current instance activity
@@ -85,7 +140,18 @@ func instanceStatsRunE(cmd *cobra.Command, args []string) error {
 			errPrint("Error: %s", err.Error())
 			os.Exit(1)
 		}
+		if instanceOpts.keep > 0 && len(activity) > int(instanceOpts.keep) {
+			activity = activity[:instanceOpts.keep]
+		}
 		obj = activity
+	case "rules":
+		// Get current instance rules
+		rules, err := gClient.GetInstanceRules()
+		if err != nil {
+			errPrint("Error: %s", err.Error())
+			os.Exit(1)
+		}
+		obj = rules
 	}
 
 	p, err := getPrinter()
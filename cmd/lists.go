@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 var listsOpts struct {
@@ -20,6 +21,10 @@ var listsOpts struct {
 	accountIDs string
 	title      string
 
+	// Mastodon 4 list settings
+	repliesPolicy string
+	exclusive     bool
+
 	// Used for several subcommands to limit the number of results
 	limit, keep uint
 	all         bool
@@ -34,10 +39,13 @@ var listsCmd = &cobra.Command{
   madonctl lists show
   madonctl lists show --list-id 3
   madonctl lists update --list-id 3 --title "Family"
+  madonctl lists rename --list-id 3 --title "Relatives"
+  madonctl lists update --list-id 3 --replies-policy none --exclusive
   madonctl lists delete --list-id 3
   madonctl lists accounts --list-id 2
   madonctl lists add-accounts --list-id 2 --account-ids 123,456
   madonctl lists remove-accounts --list-id 2 --account-ids 456
+  madonctl lists add --list-id 2 --account-id 789
   madonctl lists show --account-id 123`,
 }
 
@@ -60,6 +68,10 @@ func init() {
 
 	listsCreateSubcommand.Flags().StringVar(&listsOpts.title, "title", "", "List title")
 	listsUpdateSubcommand.Flags().StringVar(&listsOpts.title, "title", "", "List title")
+	for _, c := range []*cobra.Command{listsCreateSubcommand, listsUpdateSubcommand} {
+		c.Flags().StringVar(&listsOpts.repliesPolicy, "replies-policy", "", "Replies to show in the list (followed|list|none)")
+		c.Flags().BoolVar(&listsOpts.exclusive, "exclusive", false, "Hide list members from the home timeline")
+	}
 
 	listsAddAccountsSubcommand.Flags().StringVar(&listsOpts.accountIDs, "account-ids", "", "Comma-separated list of account IDs")
 	listsAddAccountsSubcommand.Flags().StringVarP(&listsOpts.accountID, "account-id", "a", "", "Account ID number")
@@ -86,9 +98,10 @@ var listsGetSubcommand = &cobra.Command{
 }
 
 var listsGetAccountsSubcommand = &cobra.Command{
-	Use:   "accounts --list-id N",
-	Short: "Display a list's accounts",
-	RunE:  listsGetAccountsRunE,
+	Use:     "accounts --list-id N",
+	Short:   "Display a list's accounts",
+	Aliases: []string{"members"},
+	RunE:    listsGetAccountsRunE,
 }
 
 var listsCreateSubcommand = &cobra.Command{
@@ -98,9 +111,10 @@ var listsCreateSubcommand = &cobra.Command{
 }
 
 var listsUpdateSubcommand = &cobra.Command{
-	Use:   "update --list-id N --title TITLE",
-	Short: "Update a list",
-	RunE:  listsSetDeleteRunE,
+	Use:     "update --list-id N --title TITLE",
+	Short:   "Update a list",
+	Aliases: []string{"rename"},
+	RunE:    listsSetDeleteRunE,
 }
 
 var listsDeleteSubcommand = &cobra.Command{
@@ -113,14 +127,14 @@ var listsDeleteSubcommand = &cobra.Command{
 var listsAddAccountsSubcommand = &cobra.Command{
 	Use:     "add-accounts --list-id N --account-ids ACC1,ACC2...",
 	Short:   "Add one or several accounts to a list",
-	Aliases: []string{"add-account"},
+	Aliases: []string{"add-account", "add"},
 	RunE:    listsAddRemoveAccountsRunE,
 }
 
 var listsRemoveAccountsSubcommand = &cobra.Command{
 	Use:     "remove-accounts --list-id N --account-ids ACC1,ACC2...",
 	Short:   "Remove one or several accounts from a list",
-	Aliases: []string{"remove-account"},
+	Aliases: []string{"remove-account", "remove"},
 	RunE:    listsAddRemoveAccountsRunE,
 }
 
@@ -256,8 +270,29 @@ func listsSetDeleteRunE(cmd *cobra.Command, args []string) error {
 		return errors.New("listsSetDeleteRunE: internal error")
 	}
 
-	if action != actionDelete && opt.title == "" {
-		return errors.New("the list title is required")
+	switch opt.repliesPolicy {
+	case "", "followed", "list", "none":
+	default:
+		return errors.Errorf("invalid replies policy '%s'", opt.repliesPolicy)
+	}
+
+	lp := madonext.ListParams{
+		Title:         opt.title,
+		RepliesPolicy: opt.repliesPolicy,
+	}
+	if f := cmd.Flags().Lookup("exclusive"); f != nil && f.Changed {
+		lp.Exclusive = &opt.exclusive
+	}
+
+	switch action {
+	case actionCreate:
+		if opt.title == "" {
+			return errors.New("the list title is required")
+		}
+	case actionUpdate:
+		if opt.title == "" && lp.RepliesPolicy == "" && lp.Exclusive == nil {
+			return errors.New("nothing to update")
+		}
 	}
 
 	// Log in
@@ -267,14 +302,14 @@ func listsSetDeleteRunE(cmd *cobra.Command, args []string) error {
 
 	var obj interface{}
 	var err error
-	var list *madon.List
+	var list *madonext.List
 
 	switch action {
 	case actionCreate:
-		list, err = gClient.CreateList(opt.title)
+		list, err = gClient.CreateListWithParams(lp)
 		obj = list
 	case actionUpdate:
-		list, err = gClient.UpdateList(opt.listID, opt.title)
+		list, err = gClient.UpdateListWithParams(opt.listID, lp)
 		obj = list
 	case actionDelete:
 		err = gClient.DeleteList(opt.listID)
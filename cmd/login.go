@@ -0,0 +1,110 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+)
+
+var loginOpts struct {
+	oauth bool
+}
+
+// loginCmd represents the login command
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in and save the user token",
+	Long: `Log in and save the user token
+
+The user token and the application credentials are saved to the
+configuration file.
+
+By default the login and password are used; with --oauth the OAuth2
+authorization flow is used instead (the authorization URL is displayed and
+the code is prompted for).  The OAuth2 flow is required for accounts with
+two-factor authentication.`,
+	Example: `  madonctl login -i INSTANCE -L USERNAME -P PASS
+  madonctl login -i INSTANCE --oauth
+  madonctl login -i INSTANCE --oauth --scopes read`,
+	RunE: loginRunE,
+}
+
+func init() {
+	RootCmd.AddCommand(loginCmd)
+
+	loginCmd.Flags().BoolVar(&loginOpts.oauth, "oauth", false, "Use the OAuth2 authorization flow")
+}
+
+func loginRunE(cmd *cobra.Command, args []string) error {
+	opt := loginOpts
+
+	if err := madonInitClient(); err != nil {
+		return err
+	}
+
+	if gClient.ID == "" || gClient.Secret == "" {
+		// The client was set up with a user token only; a registered
+		// application is needed to log in.
+		mc, err := madon.NewApp(AppName, AppWebsite, scopes, madon.NoRedirect, gClient.InstanceURL)
+		if err != nil {
+			return errors.Wrap(err, "app registration failed")
+		}
+		gClient = madonext.NewClient(mc)
+		errPrint("Registered new application.")
+	}
+
+	if opt.oauth {
+		url, err := gClient.LoginOAuth2("", scopes)
+		if err != nil {
+			return errors.Wrap(err, "OAuth2 authentication failed")
+		}
+		code, err := oAuth2ReadCode(url)
+		if err != nil {
+			return err
+		}
+		if _, err := gClient.LoginOAuth2(code, scopes); err != nil {
+			errPrint("Error: %s", err.Error())
+			os.Exit(1)
+		}
+	} else {
+		login = viper.GetString("login")
+		password = viper.GetString("password")
+		if login == "" || password == "" {
+			return errors.New("missing login or password (or use --oauth)")
+		}
+		if err := gClient.LoginBasic(login, password, scopes); err != nil {
+			errPrint("Error: login failed: %s", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if gClient.UserToken == nil || gClient.UserToken.AccessToken == "" {
+		errPrint("Error: no token received")
+		os.Exit(1)
+	}
+
+	settings := map[string]interface{}{
+		"instance":   gClient.InstanceURL,
+		"app_id":     gClient.ID,
+		"app_secret": gClient.Secret,
+		"token":      gClient.UserToken.AccessToken,
+	}
+	if err := configUpdateFile(settings); err != nil {
+		errPrint("Error: %s", err.Error())
+		errPrint("The new token is %s.", gClient.UserToken.AccessToken)
+		os.Exit(1)
+	}
+
+	errPrint("Login successful.")
+	return nil
+}
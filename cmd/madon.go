@@ -6,14 +6,29 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/McKael/madon/v3"
+	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"golang.org/x/net/proxy"
+
+	"github.com/McKael/madonctl/madonext"
 )
 
-var scopes = []string{"read", "write", "follow"}
+// defaultScopes are the OAuth scopes requested by default
+var defaultScopes = []string{"read", "write", "follow"}
+
+// scopes are the OAuth scopes requested for the application and user token
+var scopes = defaultScopes
 
 func madonInit(signIn bool) error {
 	if gClient == nil {
@@ -31,15 +46,21 @@ func madonInitClient() error {
 	if gClient != nil {
 		return nil
 	}
-	var err error
+
+	if err := setupHTTPClient(); err != nil {
+		return err
+	}
 
 	// Overwrite variables using Viper
-	instanceURL = viper.GetString("instance")
 	appID = viper.GetString("app_id")
 	appSecret = viper.GetString("app_secret")
 
-	if instanceURL == "" {
-		return errors.New("no instance provided")
+	var err error
+	if instanceURL, err = normalizeInstanceURL(viper.GetString("instance")); err != nil {
+		return err
+	}
+	if scopes, err = parseScopes(viper.GetStringSlice("scopes")); err != nil {
+		return err
 	}
 
 	if verbose {
@@ -48,10 +69,11 @@ func madonInitClient() error {
 
 	if appID != "" && appSecret != "" {
 		// We already have an app key/secret pair
-		gClient, err = madon.RestoreApp(AppName, instanceURL, appID, appSecret, nil)
+		mc, err := madon.RestoreApp(AppName, instanceURL, appID, appSecret, nil)
 		if err != nil {
 			return err
 		}
+		gClient = madonext.NewClient(mc)
 		// Check instance
 		if _, err := gClient.GetCurrentInstance(); err != nil {
 			return errors.Wrap(err, "could not connect to server with provided app ID/secret")
@@ -64,12 +86,22 @@ func madonInitClient() error {
 
 	if appID != "" || appSecret != "" {
 		errPrint("Warning: provided app id/secrets incomplete -- registering again")
+	} else if viper.GetString("token") != "" {
+		// A user token is enough to use the API; there is no need to
+		// register a new application (e.g. environment-only setup).
+		mc, err := madon.RestoreApp(AppName, instanceURL, "", "", nil)
+		if err != nil {
+			return err
+		}
+		gClient = madonext.NewClient(mc)
+		return nil
 	}
 
-	gClient, err = madon.NewApp(AppName, AppWebsite, scopes, madon.NoRedirect, instanceURL)
+	mc, err := madon.NewApp(AppName, AppWebsite, scopes, madon.NoRedirect, instanceURL)
 	if err != nil {
 		return errors.Wrap(err, "app registration failed")
 	}
+	gClient = madonext.NewClient(mc)
 
 	errPrint("Registered new application.")
 	return nil
@@ -102,6 +134,176 @@ func madonLogin() error {
 	return errors.Wrap(err, "login failed")
 }
 
+// setupHTTPClient configures the HTTP client used for the API calls
+func setupHTTPClient() error {
+	ua := viper.GetString("user_agent")
+	if ua == "" {
+		ua = AppName + "/" + VERSION
+	}
+	madonext.SetUserAgent(ua)
+
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return errors.New("cannot set up HTTP client: unexpected HTTP transport")
+	}
+	t = t.Clone()
+
+	// The timeout does not apply to the whole request, so that large
+	// uploads and downloads are not aborted.  A zero timeout means no
+	// timeout.
+	timeout := viper.GetDuration("timeout")
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	t.DialContext = dialer.DialContext
+	t.TLSHandshakeTimeout = timeout
+	t.ResponseHeaderTimeout = timeout
+
+	// The streaming API does not use the HTTP transport but
+	// a websocket connection
+	websocket.DefaultDialer.HandshakeTimeout = timeout
+
+	// Without --proxy, the proxy environment variables (HTTPS_PROXY...)
+	// are used by the default transport.
+	if proxyURL := viper.GetString("proxy"); proxyURL != "" {
+		if err := setTransportProxy(t, proxyURL, dialer); err != nil {
+			return err
+		}
+		websocket.DefaultDialer.Proxy = t.Proxy
+	}
+	websocket.DefaultDialer.NetDialContext = t.DialContext
+
+	if caCertFile := viper.GetString("cacert"); caCertFile != "" {
+		if err := addTransportCACert(t, caCertFile); err != nil {
+			return err
+		}
+	}
+
+	if insecureTLS {
+		errPrint("WARNING: TLS certificate verification is disabled (--insecure).")
+		errPrint("WARNING: This should only be used with a local development instance.")
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	// Use the same TLS settings (--cacert, --insecure) for the streaming API
+	websocket.DefaultDialer.TLSClientConfig = t.TLSClientConfig
+
+	http.DefaultTransport = t
+	return nil
+}
+
+// addTransportCACert adds the CA certificates from the PEM file to the
+// certificates trusted by the HTTP transport (in addition to the system
+// certificates)
+func addTransportCACert(t *http.Transport, caCertFile string) error {
+	pem, err := ioutil.ReadFile(caCertFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot read CA certificate file")
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return errors.Errorf("no valid PEM certificate found in '%s'", caCertFile)
+	}
+
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.RootCAs = pool
+	return nil
+}
+
+// setTransportProxy configures the HTTP transport to use the proxy
+// HTTP(S) and SOCKS5 proxies are supported; the SOCKS5 proxy is reached with
+// the forward dialer.
+func setTransportProxy(t *http.Transport, proxyURL string, forward *net.Dialer) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return errors.Wrap(err, "invalid proxy URL")
+	}
+	if u.Host == "" {
+		return errors.Errorf("invalid proxy URL '%s'", proxyURL)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		t.Proxy = http.ProxyURL(u)
+	case "socks5", "socks5h":
+		d, err := proxy.FromURL(u, forward)
+		if err != nil {
+			return errors.Wrap(err, "cannot set up SOCKS5 proxy")
+		}
+		cd, ok := d.(proxy.ContextDialer)
+		if !ok {
+			return errors.New("cannot set up SOCKS5 proxy")
+		}
+		t.Proxy = nil
+		t.DialContext = cd.DialContext
+	default:
+		return errors.Errorf("unsupported proxy scheme '%s' (use http, https or socks5)", u.Scheme)
+	}
+	return nil
+}
+
+// normalizeInstanceURL returns the base URL of the instance
+// The https scheme is used if none is provided, and trailing slashes are
+// removed.
+func normalizeInstanceURL(instance string) (string, error) {
+	instance = strings.TrimSpace(instance)
+	if instance == "" {
+		return "", errors.New("no instance provided")
+	}
+	if !strings.Contains(instance, "://") {
+		instance = "https://" + instance
+	}
+	u, err := url.Parse(instance)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid instance URL")
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", errors.Errorf("unsupported instance URL scheme '%s'", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", errors.Errorf("invalid instance URL '%s'", instance)
+	}
+	return u.Scheme + "://" + u.Host + strings.TrimRight(u.Path, "/"), nil
+}
+
+// parseScopes checks the OAuth scopes list
+// The items can be comma-separated; the default scopes are returned if the
+// list is empty.
+func parseScopes(list []string) ([]string, error) {
+	var sl []string
+	for _, item := range list {
+		for _, s := range strings.Split(item, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			switch {
+			case s == "read", s == "write", s == "follow", s == "push",
+				s == "admin:read", s == "admin:write",
+				strings.HasPrefix(s, "read:"), strings.HasPrefix(s, "write:"),
+				strings.HasPrefix(s, "admin:read:"), strings.HasPrefix(s, "admin:write:"):
+			default:
+				return nil, errors.Errorf("invalid scope '%s'", s)
+			}
+			sl = append(sl, s)
+		}
+	}
+	if len(sl) == 0 {
+		return defaultScopes, nil
+	}
+	return sl, nil
+}
+
 // splitIDs splits a list of IDs into an int64 array
 func splitIDs(ids string) (list []madon.ActivityID, err error) {
 	if ids == "" {
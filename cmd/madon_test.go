@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/McKael/madon/v3"
+)
+
+func TestEnvOnlyConfig(t *testing.T) {
+	var authHeader string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/accounts/verify_credentials", func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(madon.Account{ID: "1", Acct: "alice"})
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	// No configuration file
+	home, err := ioutil.TempDir("", "madonctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(home)
+
+	for k, v := range map[string]string{
+		"HOME":              home,
+		"MADONCTL_INSTANCE": ts.URL,
+		"MADONCTL_TOKEN":    "envtoken",
+	} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Setenv(k, v)
+	}
+
+	// Capture the error output
+	stderr, err := ioutil.TempFile(home, "stderr")
+	if !assert.NoError(t, err) {
+		return
+	}
+	savedStderr := os.Stderr
+	os.Stderr = stderr
+	defer func() { os.Stderr = savedStderr }()
+
+	defer func() { gClient = nil }()
+
+	initConfig()
+	if !assert.NoError(t, madonInit(true)) {
+		return
+	}
+	account, err := gClient.GetCurrentAccount()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "alice", account.Acct)
+	assert.Equal(t, "Bearer envtoken", authHeader)
+
+	// Nothing should have been printed
+	os.Stderr = savedStderr
+	out, err := ioutil.ReadFile(stderr.Name())
+	assert.NoError(t, err)
+	assert.Empty(t, string(out))
+}
+
+func TestNormalizeInstanceURL(t *testing.T) {
+	for _, tc := range []struct {
+		instance, expected string
+	}{
+		{"mastodon.social", "https://mastodon.social"},
+		{"mastodon.social/", "https://mastodon.social"},
+		{" mastodon.social// ", "https://mastodon.social"},
+		{"https://mastodon.social", "https://mastodon.social"},
+		{"https://mastodon.social/", "https://mastodon.social"},
+		{"http://localhost:3000/", "http://localhost:3000"},
+		{"localhost:3000", "https://localhost:3000"},
+	} {
+		u, err := normalizeInstanceURL(tc.instance)
+		if assert.NoError(t, err, tc.instance) {
+			assert.Equal(t, tc.expected, u, tc.instance)
+		}
+	}
+
+	for _, instance := range []string{"", "ftp://mastodon.social", "https://"} {
+		_, err := normalizeInstanceURL(instance)
+		assert.Error(t, err, instance)
+	}
+}
@@ -6,17 +6,30 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 var mediaFlags *flag.FlagSet
 
+// defaultMediaTimeout is the default maximum time to wait for the server to
+// process an uploaded media file
+const defaultMediaTimeout = 2 * time.Minute
+
+// mediaPollInterval is the delay between two media processing state checks
+var mediaPollInterval = 2 * time.Second
+
 var mediaOpts struct {
 	mediaID     madon.ActivityID
 	filePath    string
@@ -36,14 +49,49 @@ a status later.
 
 A media description or focal point (focus) can be updated
 as long as it is not yet attached to a status, with the '--update MEDIA_ID'
-option.`,
+option.
+
+The upload subcommand only prints the media ID with the plain output, so that
+it can easily be used in scripts.`,
 	Example: `  madonctl upload --file FILENAME
   madonctl media --file FILENAME --description "My screenshot"
   madonctl media --update 3217821 --focus "0.5,-0.7"
-  madonctl media --update 2468123 --description "Winter Snow"`,
+  madonctl media --update 2468123 --description "Winter Snow"
+  id=$(madonctl media upload -f image.jpg --description "My cat")
+  madonctl media update --media-id 2468123 --description "Winter Snow"
+  madonctl media show --media-id 2468123`,
 	RunE: mediaRunE,
 }
 
+var mediaUploadSubcommand = &cobra.Command{
+	Use:   "upload --file FILENAME",
+	Short: "Upload a media attachment and display its ID",
+	Example: `  madonctl media upload --file image.jpg --description "My cat"
+  id=$(madonctl media upload -f image.jpg) && madonctl toot --media-ids $id "Look"`,
+	RunE: mediaUploadRunE,
+}
+
+var mediaShowSubcommand = &cobra.Command{
+	Use:     "show --media-id ID",
+	Aliases: []string{"display"},
+	Short:   "Display a media attachment",
+	Long: `Display a media attachment
+
+This can be used to check the media URL once the server has processed it
+(e.g. for videos), before the media is attached to a status.`,
+	Example: `  madonctl media show --media-id 2468123
+  madonctl media show --media-id 2468123 -o json`,
+	RunE: mediaShowRunE,
+}
+
+var mediaUpdateSubcommand = &cobra.Command{
+	Use:   "update --media-id ID",
+	Short: "Update the description or focal point of a media attachment",
+	Example: `  madonctl media update --media-id 2468123 --description "Winter Snow"
+  madonctl media update --media-id 3217821 --focus "0.5,-0.7"`,
+	RunE: mediaUpdateRunE,
+}
+
 func init() {
 	RootCmd.AddCommand(mediaCmd)
 
@@ -53,6 +101,18 @@ func init() {
 	mediaCmd.Flags().StringVar(&mediaOpts.description, "description", "", "Plain text description")
 	mediaCmd.Flags().StringVar(&mediaOpts.focus, "focus", "", "Focal point")
 
+	// Subcommands
+	mediaCmd.AddCommand(mediaUploadSubcommand, mediaShowSubcommand, mediaUpdateSubcommand)
+
+	mediaUploadSubcommand.Flags().StringVarP(&mediaOpts.filePath, "file", "f", "", "Path of the media file")
+	mediaUploadSubcommand.Flags().StringVar(&mediaOpts.description, "description", "", "Plain text description")
+	mediaUploadSubcommand.Flags().StringVar(&mediaOpts.focus, "focus", "", "Focal point")
+
+	mediaShowSubcommand.Flags().StringVar(&mediaOpts.mediaID, "media-id", "", "Media ID")
+	mediaUpdateSubcommand.Flags().StringVar(&mediaOpts.mediaID, "media-id", "", "Media ID")
+	mediaUpdateSubcommand.Flags().StringVar(&mediaOpts.description, "description", "", "Plain text description")
+	mediaUpdateSubcommand.Flags().StringVar(&mediaOpts.focus, "focus", "", "Focal point")
+
 	// This will be used to check if the options were explicitly set or not
 	mediaFlags = mediaCmd.Flags()
 }
@@ -76,18 +136,66 @@ func mediaRunE(cmd *cobra.Command, args []string) error {
 	var err error
 
 	if opt.filePath != "" {
-		attachment, err = gClient.UploadMedia(opt.filePath, opt.description, opt.focus)
+		attachment, err = uploadMedia(opt.filePath, opt.description, opt.focus)
 	} else {
-		// Update
-		var desc, foc *string
-		if mediaFlags.Lookup("description").Changed {
-			desc = &opt.description
-		}
-		if mediaFlags.Lookup("focus").Changed {
-			foc = &opt.focus
-		}
-		attachment, err = gClient.UpdateMedia(opt.mediaID, desc, foc)
+		attachment, err = updateMedia(mediaFlags, opt.mediaID)
+	}
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return p.printObj(attachment)
+}
+
+func mediaUploadRunE(cmd *cobra.Command, args []string) error {
+	opt := mediaOpts
+
+	if opt.filePath == "" {
+		return errors.New("no media file name provided")
+	}
+
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	attachment, err := uploadMedia(opt.filePath, opt.description, opt.focus)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	// Only display the media ID with the plain output
+	if getOutputFormat() == "plain" {
+		fmt.Println(attachment.ID)
+		return nil
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
 	}
+	return p.printObj(attachment)
+}
+
+func mediaShowRunE(cmd *cobra.Command, args []string) error {
+	opt := mediaOpts
+
+	if opt.mediaID == "" {
+		return errors.New("missing media ID")
+	}
+
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	attachment, err := gClient.GetAttachment(opt.mediaID)
 	if err != nil {
 		errPrint("Error: %s", err.Error())
 		os.Exit(1)
@@ -101,14 +209,141 @@ func mediaRunE(cmd *cobra.Command, args []string) error {
 	return p.printObj(attachment)
 }
 
+func mediaUpdateRunE(cmd *cobra.Command, args []string) error {
+	opt := mediaOpts
+
+	if opt.mediaID == "" {
+		return errors.New("missing media ID")
+	}
+
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	attachment, err := updateMedia(cmd.Flags(), opt.mediaID)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return p.printObj(attachment)
+}
+
+// uploadMedia uploads a media file and returns the attachment
+func uploadMedia(filePath, description, focus string) (*madon.Attachment, error) {
+	if _, err := checkMediaFile(filePath); err != nil {
+		return nil, err
+	}
+	attachment, err := gClient.UploadMedia(filePath, description, focus)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, errors.New("no attachment returned by the server")
+	}
+	return attachment, nil
+}
+
+// checkMediaFile checks that the media file can be read and returns its
+// content type, guessed from its first bytes
+func checkMediaFile(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot open media file")
+	}
+	defer f.Close()
+
+	if fi, err := f.Stat(); err != nil {
+		return "", errors.Wrap(err, "cannot read media file")
+	} else if fi.IsDir() {
+		return "", errors.Errorf("'%s' is a directory", filePath)
+	}
+
+	buf := make([]byte, 512) // Enough for http.DetectContentType
+	n, err := f.Read(buf)
+	if err != nil && err != io.EOF {
+		return "", errors.Wrap(err, "cannot read media file")
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// checkImageFile checks that the file can be read and is an image
+func checkImageFile(filePath string) error {
+	contentType, err := checkMediaFile(filePath)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return errors.Errorf("'%s' is not an image file (%s)", filePath, contentType)
+	}
+	return nil
+}
+
+// updateMedia updates the description and/or the focal point of a media,
+// if they have been explicitly set in the flag set
+func updateMedia(flags *flag.FlagSet, mediaID madon.ActivityID) (*madon.Attachment, error) {
+	var desc, foc *string
+	if flags.Lookup("description").Changed {
+		desc = &mediaOpts.description
+	}
+	if flags.Lookup("focus").Changed {
+		foc = &mediaOpts.focus
+	}
+	return gClient.UpdateMedia(mediaID, desc, foc)
+}
+
 // uploadFile uploads a media file and returns the attachment ID
-func uploadFile(filePath string) (madon.ActivityID, error) {
-	attachment, err := gClient.UploadMedia(filePath, "", "")
+// If the media is not ready yet (e.g. a video being transcoded), the
+// function waits until it has been processed by the server, for up to
+// timeout.
+func uploadFile(filePath string, timeout time.Duration) (madon.ActivityID, error) {
+	if _, err := checkMediaFile(filePath); err != nil {
+		return "", err
+	}
+	attachment, processing, err := gClient.UploadMediaAsync(filePath, "", "")
+	if madonext.IsNotFound(err) {
+		// Old servers do not support the v2 API
+		if attachment, err = uploadMedia(filePath, "", ""); err == nil {
+			processing = attachment.URL == ""
+		}
+	}
 	if err != nil {
 		return "", err
 	}
-	if attachment == nil {
-		return "", nil
+	if timeout > 0 && processing {
+		if err := waitForMedia(attachment.ID, timeout); err != nil {
+			return "", err
+		}
 	}
 	return attachment.ID, nil
 }
+
+// waitForMedia polls the media attachment until its URL is available,
+// i.e. until the server has finished processing it.
+func waitForMedia(mediaID madon.ActivityID, timeout time.Duration) error {
+	if verbose {
+		errPrint("Waiting for media %s to be processed...", mediaID)
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		time.Sleep(mediaPollInterval)
+		attachment, err := gClient.GetAttachment(mediaID)
+		if err != nil {
+			return err
+		}
+		if attachment.URL != "" {
+			if verbose {
+				errPrint("Media %s is ready", mediaID)
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timeout while waiting for media %s to be processed", mediaID)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+)
+
+func TestUploadFileWaitsForProcessing(t *testing.T) {
+	var polls int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/media", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		// The media is being processed
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"42","type":"video","url":null}`))
+	})
+	mux.HandleFunc("/api/v1/media/42", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		a := madon.Attachment{ID: "42", Type: "video"}
+		if polls > 1 {
+			a.URL = "https://example.com/media/42.mp4"
+		}
+		json.NewEncoder(w).Encode(a)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	gClient = madonext.NewClient(&madon.Client{
+		InstanceURL: ts.URL,
+		APIBase:     ts.URL + "/api",
+		UserToken:   &madon.UserToken{AccessToken: "token"},
+	})
+	defer func() { gClient = nil }()
+
+	savedInterval := mediaPollInterval
+	mediaPollInterval = time.Millisecond
+	defer func() { mediaPollInterval = savedInterval }()
+
+	dir, err := ioutil.TempDir("", "madonctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	filePath := filepath.Join(dir, "video.mp4")
+	if !assert.NoError(t, ioutil.WriteFile(filePath, []byte("video data"), 0600)) {
+		return
+	}
+
+	id, err := uploadFile(filePath, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, madon.ActivityID("42"), id)
+	assert.Equal(t, 2, polls)
+
+	// Without timeout, the media is not polled
+	polls = 0
+	id, err = uploadFile(filePath, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, madon.ActivityID("42"), id)
+	assert.Equal(t, 0, polls)
+}
@@ -34,13 +34,17 @@ var notificationsCmd = &cobra.Command{
   madonctl accounts notifications --list --exclude-types mention,reblog
   madonctl accounts notifications --list --notification-types mentions
   madonctl accounts notifications --list --notification-types favourites
-  madonctl accounts notifications --list --notification-types follows,reblogs`,
+  madonctl accounts notifications --list --notification-types follows,reblogs
+  madonctl accounts notifications --list --all --account-id 1234`,
 	Long: `Manage notifications
 
 This commands let you list, display and dismiss notifications.
 
 Please note that --notifications-types filters the notifications locally,
-while --exclude-types is supported by the API and should be more efficient.`,
+while --exclude-types is supported by the API and should be more efficient.
+
+With --list, the --account-id option can be used to keep only the
+notifications from a given account.  This filter is applied locally, too.`,
 	RunE: notificationRunE,
 }
 
@@ -119,6 +123,17 @@ func notificationRunE(cmd *cobra.Command, args []string) error {
 			notifications = newNotifications
 		}
 
+		// Filter by account
+		if accountsOpts.accountID != "" {
+			var newNotifications []madon.Notification
+			for _, notif := range notifications {
+				if notif.Account != nil && notif.Account.ID == accountsOpts.accountID {
+					newNotifications = append(newNotifications, notif)
+				}
+			}
+			notifications = newNotifications
+		}
+
 		if accountsOpts.keep > 0 && len(notifications) > int(accountsOpts.keep) {
 			notifications = notifications[:accountsOpts.keep]
 		}
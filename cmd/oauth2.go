@@ -103,17 +103,27 @@ func oAuth2Interactive(args []string) error {
 		return errors.Wrap(err, "OAuth2 authentication failed")
 	}
 
+	code, err := oAuth2ReadCode(url)
+	if err != nil {
+		return err
+	}
+
+	// The code has been set; proceed with token exchange
+	return oAuth2ExchangeCode([]string{code})
+}
+
+// oAuth2ReadCode displays the authorization URL and prompts the user
+// for the code
+func oAuth2ReadCode(url string) (string, error) {
 	fmt.Fprintf(os.Stderr, "Visit the URL for the auth dialog:\n%s\n", url)
 	fmt.Fprintf(os.Stderr, "Enter code: ")
 	var code string
 	if _, err := fmt.Scan(&code); err != nil {
-		return err
+		return "", err
 	}
 
 	if code == "" {
-		return errors.New("no code entered")
+		return "", errors.New("no code entered")
 	}
-
-	// The code has been set; proceed with token exchange
-	return oAuth2ExchangeCode([]string{code})
+	return code, nil
 }
@@ -0,0 +1,142 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+)
+
+var pollsOpts struct {
+	pollID  madon.ActivityID
+	choices string
+}
+
+// pollsCmd represents the poll command
+var pollsCmd = &cobra.Command{
+	Use:     "poll --poll-id ID subcommand",
+	Aliases: []string{"polls"},
+	Short:   "Display and vote in polls",
+	Example: `  madonctl poll show --poll-id 42
+  madonctl poll vote --poll-id 42 --choices 0
+  madonctl poll vote --poll-id 42 --choices 0,2`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if pollsOpts.pollID == "" {
+			return errors.New("missing poll ID")
+		}
+		return madonInit(true)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(pollsCmd)
+
+	// Subcommands
+	pollsCmd.AddCommand(pollsSubcommands...)
+
+	pollsCmd.PersistentFlags().StringVar(&pollsOpts.pollID, "poll-id", "", "Poll ID")
+
+	pollsVoteSubcommand.Flags().StringVar(&pollsOpts.choices, "choices", "", "Comma-separated list of choice indexes (starting at 0)")
+}
+
+var pollsSubcommands = []*cobra.Command{
+	&cobra.Command{
+		Use:     "show",
+		Aliases: []string{"display"},
+		Short:   "Display the poll",
+		RunE:    pollsShowRunE,
+	},
+	pollsVoteSubcommand,
+}
+
+var pollsVoteSubcommand = &cobra.Command{
+	Use:   "vote --choices N[,N...]",
+	Short: "Vote in the poll",
+	RunE:  pollsVoteRunE,
+}
+
+func pollsShowRunE(cmd *cobra.Command, args []string) error {
+	poll, err := gClient.GetPoll(pollsOpts.pollID)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(poll)
+}
+
+func pollsVoteRunE(cmd *cobra.Command, args []string) error {
+	opt := pollsOpts
+
+	if opt.choices == "" {
+		return errors.New("missing choices")
+	}
+	var choices []int
+	for _, c := range strings.Split(opt.choices, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(c))
+		if err != nil {
+			return errors.Errorf("invalid choice '%s'", c)
+		}
+		choices = append(choices, n)
+	}
+
+	// Get the poll to check the choices
+	poll, err := gClient.GetPoll(opt.pollID)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	if err := checkPollChoices(poll, choices); err != nil {
+		return err
+	}
+
+	poll, err = gClient.VotePoll(opt.pollID, choices)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(poll)
+}
+
+// checkPollChoices checks the choice indexes are valid for the poll
+func checkPollChoices(poll *madonext.Poll, choices []int) error {
+	if poll.Expired {
+		return errors.New("the poll has expired")
+	}
+	if len(choices) > 1 && !poll.Multiple {
+		return errors.New("the poll does not allow multiple choices")
+	}
+	seen := make(map[int]bool)
+	for _, c := range choices {
+		if c < 0 || c >= len(poll.Options) {
+			return errors.Errorf("invalid choice %d (the poll has %d options, numbered from 0)",
+				c, len(poll.Options))
+		}
+		if seen[c] {
+			return errors.Errorf("duplicate choice %d", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
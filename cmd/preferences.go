@@ -0,0 +1,50 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// preferencesCmd represents the preferences command
+var preferencesCmd = &cobra.Command{
+	Use:     "preferences",
+	Aliases: []string{"prefs"},
+	Short:   "Display the user preferences",
+	Long: `
+The preferences command displays the user preferences stored on the server,
+such as the default visibility used when a status is posted without
+--visibility.`,
+	Example: `  madonctl preferences
+  madonctl preferences --template '{{index . "posting:default:visibility"}}{{"\n"}}'`,
+	RunE: preferencesRunE,
+}
+
+func init() {
+	RootCmd.AddCommand(preferencesCmd)
+}
+
+func preferencesRunE(cmd *cobra.Command, args []string) error {
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	prefs, err := gClient.GetPreferences()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(prefs)
+}
@@ -0,0 +1,77 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// templatePresets contains the bundled templates that can be selected with
+// the --template-preset option
+var templatePresets = map[string]string{
+	// One line per status
+	"status-short": `{{- if .reblog -}}
+{{.id}} @{{.account.acct}} boosted @{{.reblog.account.acct}}: {{.reblog.content | fromhtml | oneline}}
+{{else -}}
+{{.id}} @{{.account.acct}}: {{with .spoiler_text}}[CW: {{.}}] {{end}}{{.content | fromhtml | oneline}}
+{{end}}`,
+
+	// Short account summary
+	"account-card": `@{{.acct}}{{with .display_name}} ({{.}}){{end}}
+  ID: {{.id}}
+  URL: {{.url}}
+  Statuses: {{.statuses_count}}  Following: {{.following_count}}  Followers: {{.followers_count}}
+{{- with .note}}
+  Note: {{. | fromhtml | wrap "        " 79 | trim}}{{end}}
+{{- if .bot}}
+  Bot: true{{end}}
+{{- if .locked}}
+  Locked: true{{end}}
+
+`,
+
+	// One line per notification
+	"notification-line": `{{.id}} {{(.created_at | tolocal).Format "2006-01-02 15:04"}} {{.type}} @{{.account.acct}}
+{{- with .status}}: {{.content | fromhtml | oneline}}{{end}}
+`,
+
+	// Media attachment URLs, one per line (the attachments of a boosted
+	// status are included)
+	"media-urls": `{{range (or .reblog .).media_attachments}}{{.url}}
+{{end}}`,
+
+	// Same as media-urls, with the preview URLs
+	"media-urls-previews": `{{range (or .reblog .).media_attachments}}{{.url}}
+{{with .preview_url}}{{.}}
+{{end}}{{end}}`,
+}
+
+// getTemplatePreset returns the template of the given preset
+func getTemplatePreset(name string) (string, error) {
+	t, ok := templatePresets[name]
+	if !ok {
+		return "", errors.Errorf("unknown template preset '%s'", name)
+	}
+	return t, nil
+}
+
+// templatePresetNames returns the sorted list of template presets
+func templatePresetNames() []string {
+	var names []string
+	for n := range templatePresets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeTemplatePresets returns the list of template presets
+func completeTemplatePresets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return templatePresetNames(), cobra.ShellCompDirectiveNoFileComp
+}
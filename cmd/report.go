@@ -0,0 +1,76 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+)
+
+var reportOpts struct {
+	accountID madon.ActivityID
+	statusIDs string
+	comment   string
+	forward   bool
+}
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report --account-id ID [--status-ids ID1,ID2...] [--comment TEXT]",
+	Short: "Report an account to the moderators",
+	Long: `Report an account to the moderators
+
+The statuses and the comment are optional.
+With --forward, a copy of the report is also sent to the remote instance
+of the account.`,
+	Example: `  madonctl report --account-id 1234
+  madonctl report --account-id 1234 --status-ids 98765,98766 --comment "Spam"
+  madonctl report --account-id 1234 --comment "Spam" --forward`,
+	RunE: reportRunE,
+}
+
+func init() {
+	RootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVarP(&reportOpts.accountID, "account-id", "a", "", "Account ID number")
+	reportCmd.Flags().StringVar(&reportOpts.statusIDs, "status-ids", "", "Comma-separated list of status IDs")
+	reportCmd.Flags().StringVar(&reportOpts.comment, "comment", "", "Report comment")
+	reportCmd.Flags().BoolVar(&reportOpts.forward, "forward", false, "Forward the report to the remote instance")
+}
+
+func reportRunE(cmd *cobra.Command, args []string) error {
+	opt := reportOpts
+
+	if opt.accountID == "" {
+		return errors.New("missing account ID")
+	}
+
+	ids, err := splitIDs(opt.statusIDs)
+	if err != nil {
+		return errors.New("cannot parse status IDs")
+	}
+
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	report, err := gClient.ReportAccount(opt.accountID, ids, opt.comment, opt.forward)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return p.printObj(report)
+}
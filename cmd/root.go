@@ -7,11 +7,12 @@ package cmd
 
 import (
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 // AppName is the CLI application name
@@ -24,7 +25,7 @@ const AppWebsite = "https://github.com/McKael/madonctl"
 const defaultConfigFile = "$HOME/.config/" + AppName + "/" + AppName + ".yaml"
 
 // Madon API client
-var gClient *madon.Client
+var gClient *madonext.Client
 
 // Options
 var cfgFile string
@@ -34,33 +35,44 @@ var login, password, token string
 var verbose bool
 var outputFormat string
 var outputTemplate, outputTemplateFile, outputTheme string
+var outputTemplatePreset string
 var colorMode string
+var noColor bool
+var showCursors bool
+var jsonCompact bool
+var outputFields string
+var countOnly bool
+var maxResults uint
+var insecureTLS bool
+var yamlMultiDoc bool
+var outputWidth uint
 
 // Shell completion functions
 const shellComplFunc = `
-__madonctl_visibility() {
-	COMPREPLY=( direct private unlisted public )
-}
-__madonctl_output() {
-	COMPREPLY=( plain json yaml template theme )
-}
-__madonctl_color() {
-	COMPREPLY=( auto on off )
-}
-__madonctl_theme() {
-	local madonctl_output out
-	# This doesn't handle spaces or special chars...
-	if out=$(madonctl config themes 2>/dev/null); then
+__madonctl_emoji() {
+	local out
+	if out=$(madonctl emojis list --all --shortcodes --output plain 2>/dev/null); then
 		COMPREPLY=( $( compgen -W "${out[*]}" -- "$cur" ) )
 	fi
 }
+__madonctl_custom_func() {
+	# Complete custom emoji shortcodes in status texts
+	case ${last_command} in
+		madonctl_toot | madonctl_status_post)
+			if [[ $cur == :* ]]; then
+				__madonctl_emoji
+			fi
+			;;
+	esac
+}
 `
 
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
-	Use:               AppName,
-	Short:             "A CLI utility for Mastodon API",
-	PersistentPreRunE: checkOutputFormat,
+	Use:                AppName,
+	Short:              "A CLI utility for Mastodon API",
+	PersistentPreRunE:  checkOutputFormat,
+	PersistentPostRunE: printCursors,
 	Long: `madonctl is a CLI tool for the Mastodon REST API.
 
 You can use a configuration file to store common options.
@@ -78,6 +90,9 @@ command.
 
 (Configuration files in JSON are also accepted.)
 
+The settings can also be provided with environment variables, without any
+configuration file (e.g. MADONCTL_INSTANCE and MADONCTL_TOKEN).
+
 If you want shell auto-completion (for bash or zsh), you can generate the
 completion scripts with "madonctl completion $SHELL".
 For example if you use bash:
@@ -111,6 +126,7 @@ file.
   madonctl account show Gargron@mastodon.social
   madonctl account show -o yaml
   madonctl account --account-id 1 followers --template '{{.acct}}{{"\n"}}'
+  madonctl timeline --output json --fields id,account.acct,content
   madonctl config whoami
   madonctl timeline :mastodon`,
 	BashCompletionFunction: shellComplFunc,
@@ -136,16 +152,46 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&login, "login", "L", "", "Instance user login")
 	RootCmd.PersistentFlags().StringVarP(&password, "password", "P", "", "Instance user password")
 	RootCmd.PersistentFlags().StringVarP(&token, "token", "t", "", "User token")
+	RootCmd.PersistentFlags().Duration("timeout", 30*time.Second,
+		"Connection and response header timeout for API requests (0 to disable)")
+	RootCmd.PersistentFlags().String("user-agent", "",
+		"User-Agent header for API requests (default "+AppName+"/VERSION)")
+	RootCmd.PersistentFlags().String("proxy", "",
+		"Proxy URL (http, https or socks5; default: HTTPS_PROXY environment variable)")
+	RootCmd.PersistentFlags().BoolVar(&insecureTLS, "insecure", false,
+		"Do not verify the server TLS certificate (for local development only!)")
+	RootCmd.PersistentFlags().String("cacert", "",
+		"Additional CA certificate file (PEM) to verify the server certificate")
+	RootCmd.PersistentFlags().StringSlice("scopes", defaultScopes,
+		"OAuth scopes for app registration and login (comma-separated list)")
 	RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "",
-		"Output format (plain|json|yaml|template|theme)")
+		"Output format (plain|json|yaml|template|theme|rss)")
+	RootCmd.PersistentFlags().BoolVar(&jsonCompact, "json-compact", false,
+		"Compact JSON output (same as --output json:compact)")
+	RootCmd.PersistentFlags().BoolVar(&yamlMultiDoc, "yaml-multidoc", false,
+		"Print list items as separate YAML documents (for output=yaml)")
+	RootCmd.PersistentFlags().BoolVar(&countOnly, "count-only", false,
+		"Only display the number of items of lists")
+	RootCmd.PersistentFlags().UintVar(&maxResults, "max-results", 0,
+		"Maximum number of results to fetch across pages (--keep is applied afterwards)")
+	RootCmd.PersistentFlags().StringVar(&outputFields, "fields", "",
+		"Comma-separated list of fields to display (for output=json|yaml)")
 	RootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "",
-		"Go template (for output=template)")
+		"Go template, or @FILE to read it from a file (for output=template)")
 	RootCmd.PersistentFlags().StringVar(&outputTemplateFile, "template-file", "",
 		"Go template file (for output=template)")
+	RootCmd.PersistentFlags().StringVar(&outputTemplatePreset, "template-preset", "",
+		"Bundled template name (for output=template; see 'config presets')")
 	RootCmd.PersistentFlags().StringVar(&outputTheme, "theme", "",
 		"Theme name (for output=theme)")
+	RootCmd.PersistentFlags().UintVar(&outputWidth, "width", 0,
+		"Output width (for output=template|theme; default: terminal width)")
 	RootCmd.PersistentFlags().StringVar(&colorMode, "color", "",
-		"Color mode (auto|on|off; for output=template)")
+		"Color mode (auto|on|off; for output=plain|template|theme)")
+	RootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false,
+		"Disable colors (same as --color off)")
+	RootCmd.PersistentFlags().BoolVar(&showCursors, "show-cursors", false,
+		"Display the pagination cursors on stderr")
 
 	// Configuration file bindings
 	viper.BindPFlag("verbose", RootCmd.PersistentFlags().Lookup("verbose"))
@@ -153,23 +199,26 @@ func init() {
 	viper.BindPFlag("login", RootCmd.PersistentFlags().Lookup("login"))
 	viper.BindPFlag("password", RootCmd.PersistentFlags().Lookup("password"))
 	viper.BindPFlag("token", RootCmd.PersistentFlags().Lookup("token"))
+	viper.BindPFlag("scopes", RootCmd.PersistentFlags().Lookup("scopes"))
+	viper.BindPFlag("timeout", RootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("proxy", RootCmd.PersistentFlags().Lookup("proxy"))
+	viper.BindPFlag("cacert", RootCmd.PersistentFlags().Lookup("cacert"))
+	viper.BindPFlag("user_agent", RootCmd.PersistentFlags().Lookup("user-agent"))
 	viper.BindPFlag("color", RootCmd.PersistentFlags().Lookup("color"))
 
 	// Flag completion
-	annotationOutput := make(map[string][]string)
-	annotationOutput[cobra.BashCompCustom] = []string{"__madonctl_output"}
-	annotationColor := make(map[string][]string)
-	annotationColor[cobra.BashCompCustom] = []string{"__madonctl_color"}
-	annotationTheme := make(map[string][]string)
-	annotationTheme[cobra.BashCompCustom] = []string{"__madonctl_theme"}
-
-	RootCmd.PersistentFlags().Lookup("output").Annotations = annotationOutput
-	RootCmd.PersistentFlags().Lookup("color").Annotations = annotationColor
-	RootCmd.PersistentFlags().Lookup("theme").Annotations = annotationTheme
+	RootCmd.RegisterFlagCompletionFunc("output", completeOutputFormats)
+	RootCmd.RegisterFlagCompletionFunc("color", completeColorModes)
+	RootCmd.RegisterFlagCompletionFunc("theme", completeThemes)
+	RootCmd.RegisterFlagCompletionFunc("template-preset", completeTemplatePresets)
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	// Read in environment variables that match, with a prefix
+	viper.SetEnvPrefix(AppName)
+	viper.AutomaticEnv()
+
 	if cfgFile == "/dev/null" {
 		return
 	}
@@ -178,10 +227,6 @@ func initConfig() {
 	viper.AddConfigPath("$HOME/.config/" + AppName)
 	viper.AddConfigPath("$HOME/." + AppName)
 
-	// Read in environment variables that match, with a prefix
-	viper.SetEnvPrefix(AppName)
-	viper.AutomaticEnv()
-
 	// Enable ability to specify config file via flag
 	viper.SetConfigFile(cfgFile)
 
@@ -189,10 +234,37 @@ func initConfig() {
 	err := viper.ReadInConfig()
 	if err != nil {
 		if cfgFile != "" {
-			errPrint("Error: cannot read configuration file '%s': %v", cfgFile, err)
-			os.Exit(-1)
+			if !configFileOptional() {
+				errPrint("Error: cannot read configuration file '%s': %v", cfgFile, err)
+				os.Exit(-1)
+			}
+		} else if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			errPrint("Warning: cannot read configuration file: %v", err)
+		} else if viper.GetBool("verbose") {
+			// Without a configuration file, the settings can be
+			// provided with environment variables and flags only.
+			errPrint("No configuration file found")
 		}
 	} else if viper.GetBool("verbose") {
 		errPrint("Using config file: %s", viper.ConfigFileUsed())
 	}
 }
+
+// printCursors displays the pagination cursors of the last API response
+// to stderr, if requested with --show-cursors
+func printCursors(cmd *cobra.Command, args []string) error {
+	if !showCursors || gClient == nil {
+		return nil
+	}
+	c := gClient.LastCursors()
+	if c == nil {
+		return nil
+	}
+	if c.Next != nil {
+		errPrint("Next: %s", c.Next.Encode())
+	}
+	if c.Prev != nil {
+		errPrint("Prev: %s", c.Prev.Encode())
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-// Copyright © 2017-2018 Mikael Berthe <mikael@lilotux.net>
+// Copyright © 2017-2023 Mikael Berthe <mikael@lilotux.net>
 //
 // Licensed under the MIT license.
 // Please see the LICENSE file is this directory.
@@ -11,18 +11,31 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 var searchOpts struct {
-	resolve bool
-	//limit   uint
+	resolve       bool
+	following     bool
+	searchType    string
+	limit, offset uint
 }
 
 // searchCmd represents the search command
 var searchCmd = &cobra.Command{
 	Use:   "search [--resolve] STRING",
-	Short: "Search for contents (accounts or statuses)",
-	//Long: `TBW...`,
+	Short: "Search for contents (accounts, statuses or hashtags)",
+	Long: `Search for contents (accounts, statuses or hashtags)
+
+The results are grouped by type (accounts, statuses, hashtags).
+The --type, --following, --limit and --offset options require a server
+supporting the v2 search API.`,
+	Example: `  madonctl search madonctl
+  madonctl search --resolve https://mastodon.social/@Gargron
+  madonctl search --type hashtags --limit 5 golang
+  madonctl search --type accounts --following john`,
 	RunE: searchRunE,
 }
 
@@ -30,7 +43,10 @@ func init() {
 	RootCmd.AddCommand(searchCmd)
 
 	searchCmd.Flags().BoolVar(&searchOpts.resolve, "resolve", false, "Resolve non-local accounts")
-	//searchCmd.Flags().UintVarP(&searchOpts.limit, "limit", "l", 0, "Limit number of results")
+	searchCmd.Flags().BoolVar(&searchOpts.following, "following", false, "Only accounts you are following")
+	searchCmd.Flags().StringVar(&searchOpts.searchType, "type", "", "Result type (accounts|hashtags|statuses)")
+	searchCmd.Flags().UintVarP(&searchOpts.limit, "limit", "l", 0, "Limit number of results (per type)")
+	searchCmd.Flags().UintVar(&searchOpts.offset, "offset", 0, "Skip the first results")
 }
 
 func searchRunE(cmd *cobra.Command, args []string) error {
@@ -40,11 +56,33 @@ func searchRunE(cmd *cobra.Command, args []string) error {
 		return errors.New("no search string provided")
 	}
 
+	switch opt.searchType {
+	case "", "accounts", "hashtags", "statuses":
+	default:
+		return errors.Errorf("invalid search type '%s'", opt.searchType)
+	}
+
 	if err := madonInit(true); err != nil {
 		return err
 	}
 
-	results, err := gClient.Search(strings.Join(args, " "), opt.resolve)
+	query := strings.Join(args, " ")
+
+	var results *madon.Results
+	var err error
+
+	if opt.searchType != "" || opt.following || opt.limit > 0 || opt.offset > 0 {
+		sp := madonext.SearchParams{
+			Type:      opt.searchType,
+			Resolve:   opt.resolve,
+			Following: opt.following,
+			Limit:     int(opt.limit),
+			Offset:    int(opt.offset),
+		}
+		results, err = gClient.SearchV2(query, sp)
+	} else {
+		results, err = gClient.Search(query, opt.resolve)
+	}
 	if err != nil {
 		errPrint("Error: %s", err.Error())
 		os.Exit(1)
@@ -6,15 +6,18 @@
 package cmd
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 var statusPostFlags *flag.FlagSet
@@ -28,17 +31,40 @@ var statusOpts struct {
 	spoiler        string
 	inReplyToID    madon.ActivityID
 	mediaIDs       string
-	mediaFilePath  string
+	mediaFilePaths []string
 	textFilePath   string
 	stdin          bool
 	addMentions    bool
 	sameVisibility bool
+	mediaTimeout   time.Duration
+	idempotencyKey string
+	retrySafe      bool
+	deleteAfter    time.Duration
+	language       string
+	verifyLanguage bool
+	quiet          bool
+	replaceState   string
 
 	// Used for several subcommands to limit the number of results
 	limit, keep uint
+	keepTail    bool
 	//sinceID, maxID int64
 	all bool
 
+	// Used by the delete subcommand
+	yes             bool
+	statusIDs       string
+	continueOnError bool
+
+	// Used by the boost subcommand
+	boostVisibility string
+
+	// Used by the context subcommand
+	ancestorsOnly, descendantsOnly bool
+
+	// Used by the react/unreact subcommands
+	emoji string
+
 	// Used to indicate whether `in-reply-to` flag is present or not.
 	_hasReplyTo bool
 }
@@ -51,8 +77,10 @@ func init() {
 
 	// Global flags
 	statusCmd.PersistentFlags().StringVarP(&statusOpts.statusID, "status-id", "s", "", "Status ID number")
+	statusCmd.RegisterFlagCompletionFunc("status-id", completeStatusIDs)
 	statusCmd.PersistentFlags().UintVarP(&statusOpts.limit, "limit", "l", 0, "Limit number of API results")
-	statusCmd.PersistentFlags().UintVarP(&statusOpts.keep, "keep", "k", 0, "Limit number of results")
+	statusCmd.PersistentFlags().UintVarP(&statusOpts.keep, "keep", "k", 0, "Limit number of results (keep the first results)")
+	statusCmd.PersistentFlags().BoolVar(&statusOpts.keepTail, "keep-tail", false, "With --keep, keep the last results")
 	//statusCmd.PersistentFlags().Int64Var(&statusOpts.sinceID, "since-id", 0, "Request IDs greater than a value")
 	//statusCmd.PersistentFlags().Int64Var(&statusOpts.maxID, "max-id", 0, "Request IDs less (or equal) than a value")
 	statusCmd.PersistentFlags().BoolVar(&statusOpts.all, "all", false, "Fetch all results (for reblogged-by/favourited-by)")
@@ -62,18 +90,36 @@ func init() {
 	statusPostSubcommand.Flags().StringVar(&statusOpts.visibility, "visibility", "", "Visibility (direct|private|unlisted|public)")
 	statusPostSubcommand.Flags().StringVar(&statusOpts.spoiler, "spoiler", "", "Spoiler warning (CW)")
 	statusPostSubcommand.Flags().StringVar(&statusOpts.mediaIDs, "media-ids", "", "Comma-separated list of media IDs")
-	statusPostSubcommand.Flags().StringVarP(&statusOpts.mediaFilePath, "file", "f", "", "Media file name")
+	statusPostSubcommand.Flags().StringArrayVarP(&statusOpts.mediaFilePaths, "file", "f", nil, "Media file name (can be repeated)")
 	statusPostSubcommand.Flags().StringVar(&statusOpts.textFilePath, "text-file", "", "Text file name (message content)")
 	statusPostSubcommand.Flags().StringVarP(&statusOpts.inReplyToID, "in-reply-to", "r", "", "Status ID to reply to")
 	statusPostSubcommand.Flags().BoolVar(&statusOpts.stdin, "stdin", false, "Read message content from standard input")
 	statusPostSubcommand.Flags().BoolVar(&statusOpts.addMentions, "add-mentions", false, "Add mentions when replying")
 	statusPostSubcommand.Flags().BoolVar(&statusOpts.sameVisibility, "same-visibility", false, "Use same visibility as original message (for replies)")
+	statusPostSubcommand.Flags().DurationVar(&statusOpts.mediaTimeout, "media-timeout", defaultMediaTimeout, "Maximum time to wait for the media files to be processed (0 to disable)")
+	statusPostSubcommand.Flags().StringVar(&statusOpts.idempotencyKey, "idempotency-key", "", "Idempotency key (avoid duplicates when a post is retried)")
+	statusPostSubcommand.Flags().BoolVar(&statusOpts.retrySafe, "retry-safe", false, "Generate an idempotency key if none is provided")
+	statusPostSubcommand.Flags().StringVar(&statusOpts.language, "language", "", "Status language (ISO 639 code)")
+	statusPostSubcommand.Flags().BoolVar(&statusOpts.verifyLanguage, "verify-language", false, "Warn if the language of the posted status is not the requested one")
+	statusPostSubcommand.Flags().StringVar(&statusOpts.replaceState, "replace-state", "", "State file: edit the status recorded in the file instead of posting a new one")
+	statusPostSubcommand.Flags().BoolVar(&statusOpts.quiet, "quiet", false, "Do not display the URL of the new status on stderr")
+	statusPostSubcommand.Flags().DurationVar(&statusOpts.deleteAfter, "delete-after", 0, "Schedule the deletion of the status (see 'status reap')")
+
+	statusReblogSubcommand.Flags().StringVar(&statusOpts.boostVisibility, "visibility", "", "Boost visibility (private|unlisted|public)")
+	statusContextSubcommand.Flags().BoolVar(&statusOpts.ancestorsOnly, "ancestors-only", false, "Only display the ancestors of the status")
+	statusContextSubcommand.Flags().BoolVar(&statusOpts.descendantsOnly, "descendants-only", false, "Only display the descendants (replies) of the status")
+	statusDeleteSubcommand.Flags().BoolVarP(&statusOpts.yes, "yes", "y", false, "Do not ask for confirmation")
+	statusDeleteSubcommand.Flags().StringVar(&statusOpts.statusIDs, "status-ids", "", "Comma-separated list of status IDs to delete ('-' to read them from the standard input)")
+	statusDeleteSubcommand.Flags().BoolVar(&statusOpts.continueOnError, "continue-on-error", false, "Do not stop at the first error (with --status-ids)")
+	statusReactSubcommand.Flags().StringVar(&statusOpts.emoji, "emoji", "", "Emoji (unicode emoji or custom emoji shortcode)")
+	statusQuoteSubcommand.Flags().StringVar(&statusOpts.visibility, "visibility", "", "Visibility (default: same as the quoted status)")
+	statusQuoteSubcommand.Flags().StringVar(&statusOpts.spoiler, "spoiler", "", "Spoiler warning (CW)")
+	statusUnreactSubcommand.Flags().StringVar(&statusOpts.emoji, "emoji", "", "Emoji (unicode emoji or custom emoji shortcode)")
 
 	// Flag completion
-	annotation := make(map[string][]string)
-	annotation[cobra.BashCompCustom] = []string{"__madonctl_visibility"}
-
-	statusPostSubcommand.Flags().Lookup("visibility").Annotations = annotation
+	statusPostSubcommand.RegisterFlagCompletionFunc("visibility", completeVisibility)
+	statusReblogSubcommand.RegisterFlagCompletionFunc("visibility", completeBoostVis)
+	statusQuoteSubcommand.RegisterFlagCompletionFunc("visibility", completeVisibility)
 
 	// This one will be used to check if the options were explicitly set or not
 	statusPostFlags = statusPostSubcommand.Flags()
@@ -88,7 +134,9 @@ var statusCmd = &cobra.Command{
 	//Long:    `TBW...`, // TODO
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// This is common to status and all status subcommands but "post"
-		if statusOpts.statusID == "" && cmd.Name() != "post" {
+		// and "reap" ("delete" can also be used with a list of IDs)
+		if statusOpts.statusID == "" && cmd.Name() != "post" && cmd.Name() != "reap" &&
+			!(cmd.Name() == "delete" && statusOpts.statusIDs != "") {
 			return errors.New("missing status ID")
 		}
 		return madonInit(true)
@@ -105,8 +153,26 @@ var statusSubcommands = []*cobra.Command{
 		},
 	},
 	&cobra.Command{
-		Use:   "context",
-		Short: "Get the status context",
+		Use:   "stats",
+		Short: "Display the status counters (replies, reblogs, favourites)",
+		Example: `  madonctl status --status-id 123 stats
+  madonctl status --status-id 123 stats -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return statusSubcommandRunE(cmd.Name(), args)
+		},
+	},
+	statusContextSubcommand,
+	&cobra.Command{
+		Use:     "thread",
+		Aliases: []string{"conversation"},
+		Short:   "Display the whole conversation thread of the status",
+		Long: `Display the whole conversation thread of the status
+
+The ancestors, the status itself and its descendants are displayed in
+chronological order.  The reply depth is available in the 'depth' field,
+and it is used for the indentation with the plain output.`,
+		Example: `  madonctl status --status-id 123 thread
+  madonctl status --status-id 123 thread -o template --template '{{.depth}} {{.account.acct}}: {{.content | fromhtml}}{{"\n"}}'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return statusSubcommandRunE(cmd.Name(), args)
 		},
@@ -133,14 +199,7 @@ var statusSubcommands = []*cobra.Command{
 			return statusSubcommandRunE(cmd.Name(), args)
 		},
 	},
-	&cobra.Command{
-		Use:     "delete",
-		Aliases: []string{"rm"},
-		Short:   "Delete the status",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return statusSubcommandRunE(cmd.Name(), args)
-		},
-	},
+	statusDeleteSubcommand,
 	&cobra.Command{
 		Use:     "mute-conversation",
 		Aliases: []string{"mute"},
@@ -161,15 +220,30 @@ var statusSubcommands = []*cobra.Command{
 	statusUnreblogSubcommand,
 	statusFavouriteSubcommand,
 	statusUnfavouriteSubcommand,
+	statusReactSubcommand,
+	statusUnreactSubcommand,
 	statusPinSubcommand,
 	statusUnpinSubcommand,
 	statusPostSubcommand,
+	statusQuoteSubcommand,
+}
+
+var statusContextSubcommand = &cobra.Command{
+	Use:   "context",
+	Short: "Get the status context",
+	Example: `  madonctl status --status-id 123 context
+  madonctl status --status-id 123 context --descendants-only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return statusSubcommandRunE(cmd.Name(), args)
+	},
 }
 
 var statusReblogSubcommand = &cobra.Command{
 	Use:     "boost",
 	Aliases: []string{"reblog"},
 	Short:   "Boost (reblog) a status message",
+	Example: `  madonctl status --status-id 123 boost
+  madonctl status --status-id 123 boost --visibility unlisted`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return statusSubcommandRunE(cmd.Name(), args)
 	},
@@ -179,6 +253,11 @@ var statusUnreblogSubcommand = &cobra.Command{
 	Use:     "unboost",
 	Aliases: []string{"unreblog"},
 	Short:   "Cancel boost (reblog) of a status message",
+	Long: `Cancel boost (reblog) of a status message
+
+The updated status is displayed (the original status, not the boost), so
+that the "reblogged" field can be checked.  Cancelling a boost that does not
+exist is not an error.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return statusSubcommandRunE(cmd.Name(), args)
 	},
@@ -197,6 +276,46 @@ var statusUnfavouriteSubcommand = &cobra.Command{
 	Use:     "unfavourite",
 	Aliases: []string{"unfavorite", "unfave"},
 	Short:   "Unmark the status as favourite",
+	Long: `Unmark the status as favourite
+
+The updated status is displayed, so that the "favourited" field can be
+checked.  Unmarking a status that is not a favourite is not an error.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return statusSubcommandRunE(cmd.Name(), args)
+	},
+}
+
+var statusQuoteSubcommand = &cobra.Command{
+	Use:   "quote [COMMENT]",
+	Short: "Post a new status quoting the status",
+	Long: `Post a new status quoting the status
+
+The new status contains the comment and the URL of the quoted status.
+By default, it has the same visibility as the quoted status.`,
+	Example: `  madonctl status --status-id 123 quote "Worth reading"
+  madonctl status --status-id 123 quote --visibility unlisted "Interesting"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return statusSubcommandRunE(cmd.Name(), args)
+	},
+}
+
+var statusReactSubcommand = &cobra.Command{
+	Use:   "react --emoji EMOJI",
+	Short: "Add an emoji reaction to the status",
+	Long: `Add an emoji reaction to the status
+
+Emoji reactions are not part of the Mastodon API; they are supported by some
+implementations (e.g. Glitch-soc, Akkoma).`,
+	Example: `  madonctl status --status-id 123 react --emoji 👍
+  madonctl status --status-id 123 react --emoji blobcat`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return statusSubcommandRunE(cmd.Name(), args)
+	},
+}
+
+var statusUnreactSubcommand = &cobra.Command{
+	Use:   "unreact --emoji EMOJI",
+	Short: "Remove an emoji reaction from the status",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return statusSubcommandRunE(cmd.Name(), args)
 	},
@@ -218,6 +337,28 @@ var statusUnpinSubcommand = &cobra.Command{
 	},
 }
 
+var statusDeleteSubcommand = &cobra.Command{
+	Use:     "delete",
+	Aliases: []string{"rm"},
+	Short:   "Delete the status",
+	Long: `Delete the status
+
+Several statuses can be deleted at once with the --status-ids option
+(IDs separated by commas, or "-" to read the IDs from the standard input,
+one per line).  The deletion stops at the first error unless the
+--continue-on-error flag is used, and a summary is displayed.
+
+A confirmation is requested, unless the --yes flag is used.
+When the standard output is not a terminal, --yes is required.`,
+	Example: `  madonctl status --status-id 123 delete
+  madonctl status --status-id 123 delete --yes
+  madonctl status delete --status-ids 123,124,125
+  madonctl account statuses --keep 20 --template '{{.id}}{{"\n"}}' | madonctl status delete --status-ids - --yes --continue-on-error`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return statusSubcommandRunE(cmd.Name(), args)
+	},
+}
+
 var statusPostSubcommand = &cobra.Command{
 	Use:     "post",
 	Aliases: []string{"toot", "pouet"},
@@ -226,14 +367,19 @@ var statusPostSubcommand = &cobra.Command{
   madonctl status post --spoiler Warning "Spoiled"
   madonctl status toot --visibility private "To my followers only"
   madonctl status toot --sensitive --file image.jpg Image
+  madonctl status toot --file image1.jpg --file image2.jpg Images
   madonctl status post --media-ids ID1,ID2,ID3 Image
   madonctl status toot --text-file message.txt
   madonctl status post --in-reply-to STATUSID "@user response"
   madonctl status post --in-reply-to STATUSID --add-mentions "response"
   echo "Hello from #madonctl" | madonctl status toot --stdin
+  echo "Look at this" | madonctl status toot --stdin --file image.jpg
 
 The default visibility can be set in the configuration file with the option
-'default_visibility' (or with an environmnent variable).`,
+'default_visibility' (or with an environmnent variable).
+
+The URL of the new status is displayed on the standard error output, unless
+the --quiet flag is used.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Update the extra flag to reflect if `in-reply-to` was present or not
 		statusOpts._hasReplyTo = cmd.Flags().Lookup("in-reply-to").Changed
@@ -270,48 +416,108 @@ func statusSubcommandRunE(subcmd string, args []string) error {
 		var status *madon.Status
 		status, err = gClient.GetStatus(opt.statusID)
 		obj = status
+	case "stats":
+		var status *madon.Status
+		status, err = gClient.GetStatus(opt.statusID)
+		if err == nil {
+			obj = madonext.NewStatusStats(status)
+		}
 	case "context":
+		if opt.ancestorsOnly && opt.descendantsOnly {
+			return errors.New("--ancestors-only and --descendants-only are mutually exclusive")
+		}
 		var context *madon.Context
 		context, err = gClient.GetStatusContext(opt.statusID)
 		obj = context
+		if err == nil && opt.ancestorsOnly {
+			obj = context.Ancestors
+		} else if err == nil && opt.descendantsOnly {
+			obj = context.Descendants
+		}
+	case "thread":
+		var status *madon.Status
+		var context *madon.Context
+		status, err = gClient.GetStatus(opt.statusID)
+		if err == nil {
+			context, err = gClient.GetStatusContext(opt.statusID)
+		}
+		if err == nil {
+			obj = madonext.NewThread(status, context)
+		}
 	case "card":
 		var context *madon.Card
 		context, err = gClient.GetStatusCard(opt.statusID)
 		obj = context
 	case "reblogged-by":
 		var accountList []madon.Account
-		accountList, err = gClient.GetStatusRebloggedBy(opt.statusID, limOpts)
-		if opt.keep > 0 && len(accountList) > int(opt.keep) {
-			accountList = accountList[:opt.keep]
+		accountList, err = getStatusAccounts(subcmd, opt.statusID, capLimitParams(limOpts))
+		if maxResults > 0 && len(accountList) > int(maxResults) {
+			accountList = accountList[:maxResults]
 		}
+		first, last := keepRange(len(accountList), opt.keep, opt.keepTail)
+		accountList = accountList[first:last]
 		obj = accountList
 	case "favourited-by":
 		var accountList []madon.Account
-		accountList, err = gClient.GetStatusFavouritedBy(opt.statusID, limOpts)
-		if opt.keep > 0 && len(accountList) > int(opt.keep) {
-			accountList = accountList[:opt.keep]
+		accountList, err = getStatusAccounts(subcmd, opt.statusID, capLimitParams(limOpts))
+		if maxResults > 0 && len(accountList) > int(maxResults) {
+			accountList = accountList[:maxResults]
 		}
+		first, last := keepRange(len(accountList), opt.keep, opt.keepTail)
+		accountList = accountList[first:last]
 		obj = accountList
 	case "delete":
+		if opt.statusIDs != "" {
+			return statusBatchDelete(opt.statusIDs, opt.yes, opt.continueOnError)
+		}
+		if err = confirmAction("Delete status "+opt.statusID+"?", opt.yes); err != nil {
+			return err
+		}
 		err = gClient.DeleteStatus(opt.statusID)
 	case "boost", "unboost":
 		if subcmd == "unboost" {
-			err = gClient.UnreblogStatus(opt.statusID)
+			var s *madon.Status
+			s, err = gClient.UnreblogStatus(opt.statusID)
+			obj = s
+		} else if opt.boostVisibility != "" {
+			// Boosts cannot be direct messages
+			switch opt.boostVisibility {
+			case "private", "unlisted", "public":
+			default:
+				return errors.Errorf("invalid visibility argument value '%s'", opt.boostVisibility)
+			}
+			_, err = gClient.ReblogStatusWithVisibility(opt.statusID, opt.boostVisibility)
 		} else {
 			err = gClient.ReblogStatus(opt.statusID)
 		}
 	case "favourite", "unfavourite":
 		if subcmd == "unfavourite" {
-			err = gClient.UnfavouriteStatus(opt.statusID)
+			var s *madon.Status
+			s, err = gClient.UnfavouriteStatus(opt.statusID)
+			obj = s
 		} else {
 			err = gClient.FavouriteStatus(opt.statusID)
 		}
+	case "react", "unreact":
+		if opt.emoji == "" {
+			return errors.New("missing emoji")
+		}
+		var s *madonext.ReactionStatus
+		if subcmd == "unreact" {
+			s, err = gClient.RemoveStatusReaction(opt.statusID, opt.emoji)
+		} else {
+			s, err = gClient.AddStatusReaction(opt.statusID, opt.emoji)
+		}
+		obj = s
 	case "pin", "unpin":
 		if subcmd == "unpin" {
 			err = gClient.UnpinStatus(opt.statusID)
 		} else {
 			err = gClient.PinStatus(opt.statusID)
 		}
+		if err != nil {
+			err = pinStatusError(subcmd, err)
+		}
 	case "mute-conversation":
 		var s *madon.Status
 		s, err = gClient.MuteConversation(opt.statusID)
@@ -320,24 +526,39 @@ func statusSubcommandRunE(subcmd string, args []string) error {
 		var s *madon.Status
 		s, err = gClient.UnmuteConversation(opt.statusID)
 		obj = s
+	case "quote":
+		var s *madon.Status
+		s, err = quoteStatus(opt.statusID, strings.Join(args, " "))
+		obj = s
 	case "post": // toot
 		var s *madon.Status
-		text := strings.Join(args, " ")
-		if opt.textFilePath != "" {
-			var b []byte
-			if b, err = ioutil.ReadFile(opt.textFilePath); err != nil {
-				break
-			}
-			text = string(b)
-		} else if opt.stdin {
-			var b []byte
-			if b, err = ioutil.ReadAll(os.Stdin); err != nil {
-				break
-			}
-			text = string(b)
+		var text string
+		if opt.deleteAfter < 0 {
+			return errors.New("invalid --delete-after duration")
+		}
+		if opt.verifyLanguage && opt.language == "" {
+			return errors.New("--verify-language requires --language")
+		}
+		if text, err = readStatusText(args, os.Stdin); err != nil {
+			break
 		}
 		s, err = toot(text)
 		obj = s
+		if err == nil && !opt.quiet {
+			if u := statusURL(s); u != "" {
+				errPrint("Status URL: %s", u)
+			}
+		}
+		if err == nil && opt.verifyLanguage {
+			if lang := statusLanguage(s); !strings.EqualFold(lang, opt.language) {
+				errPrint("Warning: the status language is '%s' (requested: '%s')", lang, opt.language)
+			}
+		}
+		if err == nil && opt.deleteAfter > 0 {
+			if err := scheduleStatusDeletion(s, opt.deleteAfter); err != nil {
+				errPrint("Warning: the status deletion could not be scheduled: %s", err.Error())
+			}
+		}
 	default:
 		return errors.New("statusSubcommand: internal error")
 	}
@@ -357,3 +578,162 @@ func statusSubcommandRunE(subcmd string, args []string) error {
 	}
 	return p.printObj(obj)
 }
+
+// statusBatchDelete deletes the statuses from the comma-separated list of IDs
+// (or from the standard input if ids is "-")
+func statusBatchDelete(ids string, yes, continueOnError bool) error {
+	if statusOpts.statusID != "" {
+		return errors.New("cannot use both --status-id and --status-ids")
+	}
+
+	var idList []madon.ActivityID
+	if ids == "-" {
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return errors.Wrap(err, "cannot read standard input")
+		}
+		idList = strings.Fields(string(b))
+	} else {
+		var err error
+		if idList, err = splitIDs(ids); err != nil {
+			return errors.New("cannot parse status IDs")
+		}
+	}
+	if len(idList) == 0 {
+		return errors.New("missing status IDs")
+	}
+
+	if err := confirmAction(fmt.Sprintf("Delete %d status(es)?", len(idList)), yes); err != nil {
+		return err
+	}
+
+	var deleted, failed int
+	for _, id := range idList {
+		if err := gClient.DeleteStatus(id); err != nil {
+			errPrint("Cannot delete status %s: %s", id, err)
+			failed++
+			if !continueOnError {
+				break
+			}
+			continue
+		}
+		deleted++
+	}
+
+	errPrint("%d status(es) deleted, %d failure(s), %d skipped",
+		deleted, failed, len(idList)-deleted-failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// statusURL returns the URL of the status web page (or its URI)
+func statusURL(s *madon.Status) string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.URI
+}
+
+// statusLanguage returns the language code of the status, or "unknown"
+func statusLanguage(s *madon.Status) string {
+	if s.Language == nil || *s.Language == "" {
+		return "unknown"
+	}
+	return *s.Language
+}
+
+// pinStatusError returns a more human-friendly error for the pin/unpin
+// API validation errors (HTTP status code 422)
+func pinStatusError(subcmd string, err error) error {
+	// This is the same error check as in the madon library...
+	const validationError = "bad server status code (422)"
+
+	msg := err.Error()
+	i := strings.Index(msg, validationError)
+	if i < 0 {
+		return err
+	}
+	serverMsg := strings.TrimPrefix(msg[i+len(validationError):], ": ")
+	lcMsg := strings.ToLower(serverMsg)
+
+	switch {
+	case subcmd == "pin" && (strings.Contains(lcMsg, "already") || strings.Contains(lcMsg, "taken")):
+		return errors.New("status is already pinned")
+	case subcmd == "unpin" && strings.Contains(lcMsg, "not"):
+		return errors.New("status is not pinned")
+	}
+	return errors.Errorf("cannot %s status: %s", subcmd, serverMsg)
+}
+
+// getStatusAccounts returns the accounts that reblogged or favourited a
+// status, depending on subcmd.
+// The pages are fetched one by one, so that the progress can be displayed
+// in verbose mode.
+func getStatusAccounts(subcmd string, statusID madon.ActivityID, lopt *madon.LimitParams) ([]madon.Account, error) {
+	const pageSize = 80 // Maximum value accepted by Mastodon
+
+	fetch := gClient.GetStatusRebloggedBy
+	if subcmd == "favourited-by" {
+		fetch = gClient.GetStatusFavouritedBy
+	}
+	if lopt == nil || (!lopt.All && lopt.Limit <= pageSize) {
+		return fetch(statusID, lopt) // Single page
+	}
+
+	var accountList []madon.Account
+	page := madon.LimitParams{Limit: pageSize, SinceID: lopt.SinceID, MaxID: lopt.MaxID}
+	for {
+		if !lopt.All && lopt.Limit-len(accountList) < pageSize {
+			page.Limit = lopt.Limit - len(accountList)
+		}
+		al, err := fetch(statusID, &page)
+		if err != nil {
+			return nil, err
+		}
+		accountList = append(accountList, al...)
+		if verbose {
+			errPrint("%d account(s) fetched", len(accountList))
+		}
+
+		if len(al) == 0 || (!lopt.All && len(accountList) >= lopt.Limit) {
+			break
+		}
+		c := gClient.LastCursors()
+		if c == nil || c.Next.Get("max_id") == "" {
+			break // No more pages
+		}
+		page.MaxID = c.Next.Get("max_id")
+	}
+	return accountList, nil
+}
+
+// quoteStatus posts a new status with the comment and the URL of the
+// quoted status
+// The visibility of the quoted status is used, unless --visibility is set.
+func quoteStatus(statusID madon.ActivityID, comment string) (*madon.Status, error) {
+	quoted, err := gClient.GetStatus(statusID)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get quoted status")
+	}
+	if quoted.Reblog != nil {
+		quoted = quoted.Reblog
+	}
+	quotedURL := statusURL(quoted)
+	if quotedURL == "" {
+		return nil, errors.New("the quoted status has no URL")
+	}
+
+	text := quotedURL
+	if comment = strings.TrimSpace(comment); comment != "" {
+		text = comment + "\n\n" + quotedURL
+	}
+
+	savedOpts := statusOpts
+	defer func() { statusOpts = savedOpts }()
+	if statusOpts.visibility == "" {
+		statusOpts.visibility = quoted.Visibility
+	}
+	return toot(text)
+}
@@ -0,0 +1,144 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madon/v3"
+)
+
+var statusMediaOpts struct {
+	dir     string
+	preview bool
+}
+
+var statusMediaSubcommand = &cobra.Command{
+	Use:   "media",
+	Short: "Manage the media attachments of a status",
+}
+
+var statusMediaDownloadSubcommand = &cobra.Command{
+	Use:   "download",
+	Short: "Download the media attachments of a status",
+	Long: `Download the media attachments of a status
+
+The files are saved in the directory given with --dir (the current directory
+by default), and are named after the attachment ID.  The path of each saved
+file is displayed.`,
+	Example: `  madonctl status --status-id 123 media download
+  madonctl status --status-id 123 media download --dir ./out
+  madonctl status --status-id 123 media download --preview --dir ./thumbnails`,
+	RunE: statusMediaDownloadRunE,
+}
+
+func init() {
+	statusCmd.AddCommand(statusMediaSubcommand)
+	statusMediaSubcommand.AddCommand(statusMediaDownloadSubcommand)
+
+	statusMediaDownloadSubcommand.Flags().StringVar(&statusMediaOpts.dir, "dir", ".", "Destination directory")
+	statusMediaDownloadSubcommand.Flags().BoolVar(&statusMediaOpts.preview, "preview", false, "Download the previews (thumbnails)")
+}
+
+func statusMediaDownloadRunE(cmd *cobra.Command, args []string) error {
+	opt := statusMediaOpts
+
+	if len(args) > 0 {
+		return errors.New("too many arguments")
+	}
+
+	status, err := gClient.GetStatus(statusOpts.statusID)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	if status.Reblog != nil {
+		status = status.Reblog
+	}
+	if len(status.MediaAttachments) == 0 {
+		if verbose {
+			errPrint("The status has no media attachment")
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(opt.dir, 0755); err != nil {
+		return errors.Wrap(err, "cannot create destination directory")
+	}
+
+	for _, a := range status.MediaAttachments {
+		mediaURL := attachmentURL(a, opt.preview)
+		if mediaURL == "" {
+			errPrint("Error: no URL for attachment %s", a.ID)
+			os.Exit(1)
+		}
+		name := string(a.ID)
+		if opt.preview {
+			name += "-preview"
+		}
+		filePath := filepath.Join(opt.dir, name+urlExtension(mediaURL))
+		if err := downloadFile(mediaURL, filePath); err != nil {
+			errPrint("Error: attachment %s: %s", a.ID, err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(filePath)
+	}
+	return nil
+}
+
+// attachmentURL returns the URL of the attachment file (or of its preview)
+// The remote URL is used if the media has not been cached by the server.
+func attachmentURL(a madon.Attachment, preview bool) string {
+	if preview {
+		return a.PreviewURL
+	}
+	if a.URL == "" && a.RemoteURL != nil {
+		return *a.RemoteURL
+	}
+	return a.URL
+}
+
+// urlExtension returns the file name extension of the URL path
+func urlExtension(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return path.Ext(u.Path)
+}
+
+// downloadFile saves the document at fileURL to filePath
+func downloadFile(fileURL, filePath string) error {
+	res, err := http.Get(fileURL)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("bad server status code (%d)", res.StatusCode)
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, res.Body); err != nil {
+		f.Close()
+		os.Remove(filePath) // Do not leave a partial file
+		return err
+	}
+	return f.Close()
+}
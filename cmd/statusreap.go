@@ -0,0 +1,198 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+)
+
+// Default location of the scheduled deletions file
+const defaultReapStateFile = "$HOME/.config/" + AppName + "/reap.json"
+
+var statusReapOpts struct {
+	dryRun bool
+}
+
+// reapEntry is a status scheduled for deletion (see --delete-after)
+type reapEntry struct {
+	StatusID  madon.ActivityID `json:"status_id"`
+	Instance  string           `json:"instance"`
+	AccountID madon.ActivityID `json:"account_id"`
+	DeleteAt  time.Time        `json:"delete_at"`
+}
+
+var statusReapSubcommand = &cobra.Command{
+	Use:   "reap",
+	Short: "Delete the statuses posted with --delete-after that have expired",
+	Long: `Delete the statuses posted with --delete-after that have expired
+
+The statuses posted with the --delete-after option are recorded in a local
+state file (` + defaultReapStateFile + ` by default; it can be set with the
+'reap_state_file' configuration option).  This command deletes the recorded
+statuses of the current account whose deletion time has passed.
+
+The statuses are not deleted automatically: this command has to be run
+periodically, e.g. from a cron job.`,
+	Example: `  madonctl toot --delete-after 24h "This message will self-destruct"
+  madonctl status reap
+  madonctl status reap --dry-run`,
+	RunE: statusReapRunE,
+}
+
+func init() {
+	statusCmd.AddCommand(statusReapSubcommand)
+
+	statusReapSubcommand.Flags().BoolVar(&statusReapOpts.dryRun, "dry-run", false, "Only display the statuses that would be deleted")
+}
+
+func statusReapRunE(cmd *cobra.Command, args []string) error {
+	opt := statusReapOpts
+
+	if len(args) > 0 {
+		return errors.New("too many arguments")
+	}
+
+	stateFile := reapStateFile()
+	entries, err := loadReapState(stateFile)
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	// Statuses of other accounts cannot be deleted (and the server would
+	// reply with a 404 error, as if they had already been deleted).
+	account, err := gClient.GetCurrentAccount()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	deleted := make(map[madon.ActivityID]bool)
+	var failed int
+	for _, e := range entries {
+		if e.Instance != gClient.InstanceURL || e.AccountID != account.ID || e.DeleteAt.After(now) {
+			continue
+		}
+		if opt.dryRun {
+			errPrint("Status %s would be deleted (expired on %v)", e.StatusID, e.DeleteAt.Local())
+			continue
+		}
+		err := gClient.DeleteStatus(e.StatusID)
+		if err != nil && !madonext.IsNotFound(err) {
+			errPrint("Error: cannot delete status %s: %s", e.StatusID, err.Error())
+			failed++ // Try again next time
+			continue
+		}
+		if verbose {
+			errPrint("Status %s deleted", e.StatusID)
+		}
+		deleted[e.StatusID] = true
+	}
+
+	pending := len(entries) - len(deleted)
+	if len(deleted) > 0 {
+		// The state file may have been updated in the meantime,
+		// so only the deleted entries are removed.
+		err := updateReapState(stateFile, func(entries []reapEntry) []reapEntry {
+			var remaining []reapEntry
+			for _, e := range entries {
+				if e.Instance == gClient.InstanceURL && deleted[e.StatusID] {
+					continue
+				}
+				remaining = append(remaining, e)
+			}
+			pending = len(remaining)
+			return remaining
+		})
+		if err != nil {
+			errPrint("Error: %s", err.Error())
+			os.Exit(1)
+		}
+	}
+	if verbose {
+		errPrint("%d status(es) deleted, %d failure(s), %d pending", len(deleted), failed, pending)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// scheduleStatusDeletion records the status in the state file, so that it is
+// deleted by the 'status reap' command after the delay d
+// If the status has already been scheduled, the deletion time is unchanged.
+func scheduleStatusDeletion(s *madon.Status, d time.Duration) error {
+	e := reapEntry{
+		StatusID: s.ID,
+		Instance: gClient.InstanceURL,
+		DeleteAt: time.Now().Add(d),
+	}
+	if s.Account != nil {
+		e.AccountID = s.Account.ID
+	}
+	return updateReapState(reapStateFile(), func(entries []reapEntry) []reapEntry {
+		for _, o := range entries {
+			if o.StatusID == e.StatusID && o.Instance == e.Instance {
+				return entries // Already scheduled (edited status)
+			}
+		}
+		return append(entries, e)
+	})
+}
+
+// reapStateFile returns the path of the scheduled deletions file
+func reapStateFile() string {
+	if f := viper.GetString("reap_state_file"); f != "" {
+		return os.ExpandEnv(f)
+	}
+	return os.ExpandEnv(defaultReapStateFile)
+}
+
+func loadReapState(fileName string) ([]reapEntry, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Nothing has been scheduled yet
+		}
+		return nil, errors.Wrap(err, "cannot read state file")
+	}
+	var entries []reapEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse state file '%s'", fileName)
+	}
+	return entries, nil
+}
+
+// updateReapState applies the function update to the entries of the state
+// file, while holding the state file lock
+func updateReapState(fileName string, update func([]reapEntry) []reapEntry) error {
+	unlock, err := lockStateFile(fileName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := loadReapState(fileName)
+	if err != nil {
+		return err
+	}
+	entries = update(entries)
+	if entries == nil {
+		entries = []reapEntry{}
+	}
+	return writeStateFile(fileName, entries)
+}
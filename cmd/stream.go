@@ -35,7 +35,10 @@ The stream command stays connected to the server and listen to a stream of
 events (user, local or federated).
 A list-based stream can be displayed by prefixing the list ID with a '!'.
 It can also get a hashtag-based stream if the keyword is prefixed with
-':' or '#'.`,
+':' or '#'.
+
+The --timeout setting only applies to the stream connection, not to the
+events.`,
 	Example: `  madonctl stream           # User timeline stream
   madonctl stream local     # Local timeline stream
   madonctl stream public    # Public timeline stream
@@ -6,6 +6,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/pkg/errors"
@@ -21,6 +22,8 @@ var suggestionsOpts struct {
 	//limit uint
 	keep uint
 	//all bool
+
+	yes bool // For delete
 }
 
 // suggestionsCmd represents the suggestions command
@@ -43,6 +46,7 @@ func init() {
 
 	suggestionsDeleteSubcommand.Flags().StringVarP(&suggestionsOpts.accountID, "account-id", "a", "", "Account ID number")
 	suggestionsDeleteSubcommand.Flags().StringVar(&suggestionsOpts.accountIDs, "account-ids", "", "Comma-separated list of account IDs")
+	suggestionsDeleteSubcommand.Flags().BoolVarP(&suggestionsOpts.yes, "yes", "y", false, "Do not ask for confirmation")
 }
 
 var suggestionsSubcommands = []*cobra.Command{
@@ -137,14 +141,18 @@ func suggestionsDeleteRunE(cmd *cobra.Command, args []string) error {
 		return errors.New("missing account IDs")
 	}
 
+	if err := confirmAction(fmt.Sprintf("Remove %d account(s) from the suggestions?", len(ids)), opt.yes); err != nil {
+		return err
+	}
+
 	// We need to be logged in
 	if err := madonInit(true); err != nil {
 		return err
 	}
 
 	for _, id := range ids {
-		if e := gClient.DeleteSuggestion(id); err != nil {
-			errPrint("Cannot remove account %d: %s", id, e)
+		if e := gClient.DeleteSuggestion(id); e != nil {
+			errPrint("Cannot remove account %s: %s", id, e)
 			err = e
 		}
 	}
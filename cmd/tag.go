@@ -0,0 +1,96 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/McKael/madonctl/madonext"
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:     "tag",
+	Aliases: []string{"hashtag"},
+	Short:   "Display and follow hashtags",
+	Long: `
+The tag command displays hashtag information (follow state and usage history)
+and can be used to follow or unfollow hashtags.  The statuses with a followed
+hashtag appear in the home timeline.
+
+See also the hashtag timeline ("madonctl timeline :TAG").`,
+	Example: `  madonctl tag show mastodon
+  madonctl tag follow golang
+  madonctl tag unfollow golang`,
+}
+
+func init() {
+	RootCmd.AddCommand(tagCmd)
+
+	// Subcommands
+	tagCmd.AddCommand(tagSubcommands...)
+}
+
+var tagSubcommands = []*cobra.Command{
+	&cobra.Command{
+		Use:     "show TAG",
+		Short:   "Display a hashtag",
+		Aliases: []string{"display", "get"},
+		RunE:    tagRunE,
+	},
+	&cobra.Command{
+		Use:   "follow TAG",
+		Short: "Follow a hashtag",
+		RunE:  tagRunE,
+	},
+	&cobra.Command{
+		Use:   "unfollow TAG",
+		Short: "Stop following a hashtag",
+		RunE:  tagRunE,
+	},
+}
+
+func tagRunE(cmd *cobra.Command, args []string) error {
+	subcmd := cmd.Name()
+
+	if len(args) != 1 || args[0] == "" {
+		return errors.Errorf("wrong usage: %s needs 1 argument", subcmd)
+	}
+
+	// We need to be logged in
+	if err := madonInit(true); err != nil {
+		return err
+	}
+
+	var tag *madonext.Tag
+	var err error
+
+	switch subcmd {
+	case "show":
+		tag, err = gClient.GetTag(args[0])
+	case "follow":
+		tag, err = gClient.FollowTag(args[0])
+	case "unfollow":
+		tag, err = gClient.UnfollowTag(args[0])
+	default:
+		return errors.New("tagRunE: internal error")
+	}
+
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %v", err)
+		os.Exit(1)
+	}
+	return p.printObj(tag)
+}
@@ -7,88 +7,223 @@ package cmd
 
 import (
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 var timelineOpts struct {
-	local, onlyMedia bool
-	limit, keep      uint
-	sinceID, maxID   madon.ActivityID
+	local, remote  bool
+	onlyMedia      bool
+	limit, keep    uint
+	keepTail       bool
+	all            bool
+	sinceID, maxID madon.ActivityID
+	minID          madon.ActivityID
+
+	// Local filters
+	excludeVisibilities string
+	excludeReblogs      bool
+	excludeReplies      bool
+	since, until        string
+
+	linkURL string // For timeline link
 }
 
 // timelineCmd represents the timelines command
 var timelineCmd = &cobra.Command{
-	Use:     "timeline [home|public|direct|:HASHTAG|!list_id] [--local]",
+	Use:     "timeline [home|public|direct|:HASHTAG|!list_id] [--local|--remote]",
 	Aliases: []string{"tl"},
 	Short:   "Fetch a timeline",
 	Long: `
 The timeline command fetches a timeline (home, local or federated).
 The timeline "direct" contains only direct messages (that is, messages with
-visibility set to "direct").
+visibility set to "direct").  With recent servers, where this timeline has
+been removed, the last statuses of the direct conversations are displayed.
 It can also get a hashtag-based timeline if the keyword or prefixed with
-':' or '#', or a list-based timeline (use !ID with the list ID).`,
+':' or '#', or a list-based timeline (use !ID with the list ID).
+
+The --since and --until options filter the statuses by creation date.
+They accept a timestamp (RFC3339, "YYYY-MM-DD hh:mm" or "YYYY-MM-DD") or a
+duration before the current time (e.g. "36h" or "2d").  With --since, the
+older pages are not fetched.
+With --all and --keep N, the pages are fetched until N statuses matching the
+local filters (--exclude-*, --since, --until) have been found.
+
+With --since-id, the newest statuses are returned; with --min-id, the
+statuses immediately following the given ID are returned, which is useful to
+read a timeline forward without gaps.  With --min-id, a single page is
+fetched and --all cannot be used.`,
 	Example: `  madonctl timeline
   madonctl timeline public --local
+  madonctl timeline public --remote
   madonctl timeline '!42'
   madonctl timeline :mastodon
-  madonctl timeline direct`,
+  madonctl timeline direct
+  madonctl timeline :mastodon --all --keep 500
+  madonctl timeline --limit 40 --keep 5 --keep-tail
+  madonctl timeline --min-id 110123456789012345 --limit 20
+  madonctl timeline :mastodon --all --max-results 1000 --exclude-reblogs --keep 100
+  madonctl timeline --exclude-reblogs --exclude-replies
+  madonctl timeline --all --since 48h --until 24h
+  madonctl timeline :mastodon --all --since 2023-04-01 --until 2023-04-02
+  madonctl timeline --exclude-visibilities unlisted,private`,
 	RunE:      timelineRunE,
 	ValidArgs: []string{"home", "public", "direct"},
 }
 
+var timelineLinkSubcommand = &cobra.Command{
+	Use:   "link --url URL",
+	Short: "Fetch the statuses discussing a trending link",
+	Long: `Fetch the statuses discussing a trending link
+
+This requires Mastodon 4.3 or later.`,
+	Example: `  madonctl timeline link --url https://example.com/article
+  madonctl timeline link --url https://example.com/article --all --keep 100`,
+	RunE: timelineLinkRunE,
+}
+
 func init() {
 	RootCmd.AddCommand(timelineCmd)
 
+	// Subcommands
+	timelineCmd.AddCommand(timelineLinkSubcommand)
+
 	timelineCmd.Flags().BoolVar(&timelineOpts.local, "local", false, "Posts from the local instance")
+	timelineCmd.Flags().BoolVar(&timelineOpts.remote, "remote", false, "Posts from remote instances only")
 	timelineCmd.Flags().BoolVar(&timelineOpts.onlyMedia, "only-media", false, "Only statuses with media attachments")
-	timelineCmd.Flags().UintVarP(&timelineOpts.limit, "limit", "l", 0, "Limit number of API results")
-	timelineCmd.Flags().UintVarP(&timelineOpts.keep, "keep", "k", 0, "Limit number of results")
+	timelineCmd.PersistentFlags().UintVarP(&timelineOpts.limit, "limit", "l", 0, "Limit number of API results")
+	timelineCmd.PersistentFlags().UintVarP(&timelineOpts.keep, "keep", "k", 0, "Limit number of results (keep the first results, i.e. the newest ones)")
+	timelineCmd.PersistentFlags().BoolVar(&timelineOpts.keepTail, "keep-tail", false, "With --keep, keep the last results (i.e. the oldest ones)")
+	timelineCmd.PersistentFlags().BoolVar(&timelineOpts.all, "all", false, "Fetch all results")
+	timelineCmd.Flags().StringVar(&timelineOpts.excludeVisibilities, "exclude-visibilities", "", "Skip statuses with these visibilities (comma-separated list)")
+	timelineCmd.Flags().BoolVar(&timelineOpts.excludeReblogs, "exclude-reblogs", false, "Skip reblogs (boosts)")
+	timelineCmd.Flags().BoolVar(&timelineOpts.excludeReplies, "exclude-replies", false, "Skip replies")
+	timelineCmd.Flags().StringVar(&timelineOpts.since, "since", "", "Only statuses created after this date (timestamp or duration)")
+	timelineCmd.Flags().StringVar(&timelineOpts.until, "until", "", "Only statuses created before this date (timestamp or duration)")
 	timelineCmd.PersistentFlags().StringVar(&timelineOpts.sinceID, "since-id", "", "Request IDs greater than a value")
 	timelineCmd.PersistentFlags().StringVar(&timelineOpts.maxID, "max-id", "", "Request IDs less (or equal) than a value")
+	timelineCmd.PersistentFlags().StringVar(&timelineOpts.minID, "min-id", "", "Request the oldest IDs greater than a value (forward pagination)")
+
+	timelineLinkSubcommand.Flags().StringVar(&timelineOpts.linkURL, "url", "", "Link URL")
 }
 
-func timelineRunE(cmd *cobra.Command, args []string) error {
+// statusFilter returns the statuses matching the local filters
+type statusFilter func([]madon.Status) []madon.Status
+
+// timelineLimitParams returns the limit parameters for the timeline options
+// If filtered is true, the results will be filtered locally and --keep cannot
+// be used to limit the number of statuses fetched.
+func timelineLimitParams(filtered bool) *madon.LimitParams {
 	opt := timelineOpts
 	var limOpts *madon.LimitParams
 
-	if opt.limit > 0 || opt.sinceID != "" || opt.maxID != "" {
+	if opt.all || opt.limit > 0 || opt.sinceID != "" || opt.maxID != "" {
 		limOpts = new(madon.LimitParams)
+		limOpts.All = opt.all
 	}
 
 	if opt.limit > 0 {
 		limOpts.Limit = int(opt.limit)
 	}
+	if opt.all && opt.keep > 0 && !opt.keepTail && !filtered {
+		// Use --keep as an overall cap, there is no need to fetch
+		// the whole timeline.
+		limOpts.All = false
+		if limOpts.Limit < int(opt.keep) {
+			limOpts.Limit = int(opt.keep)
+		}
+	}
 	if opt.maxID != "" {
 		limOpts.MaxID = opt.maxID
 	}
 	if opt.sinceID != "" {
 		limOpts.SinceID = opt.sinceID
 	}
+	return limOpts
+}
+
+func timelineRunE(cmd *cobra.Command, args []string) error {
+	opt := timelineOpts
 
 	tl := "home"
 	if len(args) > 0 {
 		tl = args[0]
 	}
 
+	if opt.local && opt.remote {
+		return errors.New("--local and --remote are mutually exclusive")
+	}
+	if opt.minID != "" && opt.all {
+		return errors.New("--min-id cannot be used with --all")
+	}
+
+	var since, until time.Time
+	if opt.since != "" {
+		var err error
+		if since, err = parseTimeArg(opt.since); err != nil {
+			return errors.Wrap(err, "bad --since value")
+		}
+	}
+	if opt.until != "" {
+		var err error
+		if until, err = parseTimeArg(opt.until); err != nil {
+			return errors.Wrap(err, "bad --until value")
+		}
+	}
+
+	// Local filtering (this works even if the server does not support
+	// these filters)
+	var filter statusFilter
+	if opt.excludeVisibilities != "" || opt.excludeReblogs || opt.excludeReplies || !since.IsZero() || !until.IsZero() {
+		filter = func(sl []madon.Status) []madon.Status {
+			sl = filterStatuses(sl, opt.excludeVisibilities, opt.excludeReblogs, opt.excludeReplies)
+			return filterStatusesByDate(sl, since, until)
+		}
+	}
+	limOpts := timelineLimitParams(filter != nil)
+
+	// With --all and --keep, the pages are fetched until enough statuses
+	// match the local filters
+	var keep int
+	if filter != nil && opt.all && !opt.keepTail {
+		keep = int(opt.keep)
+	}
+
 	// Home timeline and list-based timeline require to be logged in
 	if err := madonInit(tl == "home" || tl == "direct" || strings.HasPrefix(tl, "!")); err != nil {
 		return err
 	}
 
-	sl, err := gClient.GetTimelines(tl, opt.local, opt.onlyMedia, limOpts)
+	tp := madonext.TimelineParams{
+		Local:     opt.local,
+		Remote:    opt.remote,
+		OnlyMedia: opt.onlyMedia,
+		MinID:     opt.minID,
+	}
+	sl, err := getTimeline(tl, tp, capLimitParams(limOpts), since, filter, keep)
 	if err != nil {
 		errPrint("Error: %s", err.Error())
 		os.Exit(1)
 	}
 
-	if opt.keep > 0 && len(sl) > int(opt.keep) {
-		sl = sl[:opt.keep]
+	if filter != nil {
+		sl = filter(sl)
 	}
 
+	if maxResults > 0 && len(sl) > int(maxResults) {
+		sl = sl[:maxResults]
+	}
+
+	first, last := keepRange(len(sl), opt.keep, opt.keepTail)
+	sl = sl[first:last]
+
 	p, err := getPrinter()
 	if err != nil {
 		errPrint("Error: %s", err.Error())
@@ -96,3 +231,170 @@ func timelineRunE(cmd *cobra.Command, args []string) error {
 	}
 	return p.printObj(sl)
 }
+
+func timelineLinkRunE(cmd *cobra.Command, args []string) error {
+	opt := timelineOpts
+
+	if opt.linkURL == "" {
+		return errors.New("missing link URL")
+	}
+	if opt.minID != "" && opt.all {
+		return errors.New("--min-id cannot be used with --all")
+	}
+
+	if err := madonInit(false); err != nil {
+		return err
+	}
+
+	sl, err := gClient.GetLinkTimeline(opt.linkURL, opt.minID, capLimitParams(timelineLimitParams(false)))
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	if maxResults > 0 && len(sl) > int(maxResults) {
+		sl = sl[:maxResults]
+	}
+	first, last := keepRange(len(sl), opt.keep, opt.keepTail)
+	sl = sl[first:last]
+
+	p, err := getPrinter()
+	if err != nil {
+		errPrint("Error: %s", err.Error())
+		os.Exit(1)
+	}
+	return p.printObj(sl)
+}
+
+// filterStatuses removes the statuses matching the exclusion criteria
+// from the list.  excludedVisibilities is a comma-separated list.
+func filterStatuses(sl []madon.Status, excludedVisibilities string, excludeReblogs, excludeReplies bool) []madon.Status {
+	skipVis := make(map[string]bool)
+	for _, v := range strings.Split(excludedVisibilities, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			skipVis[strings.ToLower(v)] = true
+		}
+	}
+
+	var filtered []madon.Status
+	for _, s := range sl {
+		if skipVis[s.Visibility] {
+			continue
+		}
+		if excludeReblogs && s.Reblog != nil {
+			continue
+		}
+		if excludeReplies && s.InReplyToID != nil && *s.InReplyToID != "" {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
+// getTimeline fetches the timeline tl (see getTimelinePages if since or
+// keep is set).
+// The direct timeline has been removed from recent Mastodon versions; if the
+// server does not know it, the last statuses of the direct conversations are
+// returned instead.
+func getTimeline(tl string, tp madonext.TimelineParams, lopt *madon.LimitParams, since time.Time, filter statusFilter, keep int) ([]madon.Status, error) {
+	var sl []madon.Status
+	var err error
+	if since.IsZero() && keep == 0 {
+		sl, err = gClient.GetTimeline(tl, tp, lopt)
+	} else {
+		sl, err = getTimelinePages(tl, tp, lopt, since, filter, keep)
+	}
+	if tl == "direct" && madonext.IsNotFound(err) {
+		if verbose {
+			errPrint("The direct timeline is not available, using conversations")
+		}
+		return getConversationStatuses(tp.OnlyMedia, lopt)
+	}
+	return sl, err
+}
+
+// getConversationStatuses returns the last statuses of the direct
+// conversations, newest first
+func getConversationStatuses(onlyMedia bool, lopt *madon.LimitParams) ([]madon.Status, error) {
+	conversations, err := gClient.GetConversations(lopt)
+	if err != nil {
+		return nil, err
+	}
+
+	var sl []madon.Status
+	for _, c := range conversations {
+		if c.LastStatus == nil {
+			continue
+		}
+		if onlyMedia && len(c.LastStatus.MediaAttachments) == 0 {
+			continue
+		}
+		sl = append(sl, *c.LastStatus)
+	}
+	sort.SliceStable(sl, func(i, j int) bool {
+		return sl[i].CreatedAt.After(sl[j].CreatedAt)
+	})
+	return sl, nil
+}
+
+// getTimelinePages fetches a timeline page by page, and stops when a status
+// older than since is found, when keep statuses matching the filter have been
+// fetched, or when the limits are reached.  A zero value disables the
+// since and keep conditions.
+func getTimelinePages(tl string, tp madonext.TimelineParams, lopt *madon.LimitParams, since time.Time, filter statusFilter, keep int) ([]madon.Status, error) {
+	const pageSize = 40
+
+	var all bool
+	var total, matches int
+	page := madon.LimitParams{Limit: pageSize}
+	if lopt != nil {
+		all, total = lopt.All, lopt.Limit
+		page.SinceID, page.MaxID = lopt.SinceID, lopt.MaxID
+	}
+
+	var sl []madon.Status
+	for {
+		if !all && total > 0 && total-len(sl) < pageSize {
+			page.Limit = total - len(sl)
+		}
+		statuses, err := gClient.GetTimeline(tl, tp, &page)
+		if err != nil {
+			return nil, err
+		}
+		sl = append(sl, statuses...)
+		if len(statuses) == 0 {
+			break
+		}
+		if keep > 0 && filter != nil {
+			if matches += len(filter(statuses)); matches >= keep {
+				break // Enough statuses have been found
+			}
+		}
+		oldest := statuses[len(statuses)-1]
+		if !since.IsZero() && oldest.CreatedAt.Before(since) {
+			break // No need to fetch older pages
+		}
+		if !all && len(sl) >= total {
+			break
+		}
+		page.MaxID = oldest.ID
+	}
+	return sl, nil
+}
+
+// filterStatusesByDate removes the statuses created before since or after
+// until.  A zero time value disables the corresponding boundary.
+func filterStatusesByDate(sl []madon.Status, since, until time.Time) []madon.Status {
+	var filtered []madon.Status
+	for _, s := range sl {
+		if !since.IsZero() && s.CreatedAt.Before(since) {
+			continue
+		}
+		if !until.IsZero() && s.CreatedAt.After(until) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
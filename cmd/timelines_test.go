@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+)
+
+func TestDirectTimelineFallback(t *testing.T) {
+	date := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/timelines/direct", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Record not found"}`))
+	})
+	mux.HandleFunc("/api/v1/conversations", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]madonext.Conversation{
+			{ID: "1", LastStatus: &madon.Status{ID: "10", CreatedAt: date}},
+			{ID: "2"}, // No last status
+			{ID: "3", LastStatus: &madon.Status{ID: "30", CreatedAt: date.Add(time.Hour)}},
+		})
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	gClient = madonext.NewClient(&madon.Client{
+		InstanceURL: ts.URL,
+		APIBase:     ts.URL + "/api",
+		UserToken:   &madon.UserToken{AccessToken: "token"},
+	})
+	defer func() { gClient = nil }()
+
+	sl, err := getTimeline("direct", madonext.TimelineParams{}, nil, time.Time{}, nil, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, sl, 2) {
+		// Newest first
+		assert.Equal(t, madon.ActivityID("30"), sl[0].ID)
+		assert.Equal(t, madon.ActivityID("10"), sl[1].ID)
+	}
+
+	// Other timelines do not fall back to the conversations
+	_, err = getTimeline("!42", madonext.TimelineParams{}, nil, time.Time{}, nil, 0)
+	assert.Error(t, err)
+}
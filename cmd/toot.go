@@ -6,6 +6,10 @@
 package cmd
 
 import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -14,6 +18,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 // toot is a kind of alias for status post
@@ -27,18 +32,23 @@ func init() {
 	tootAliasCmd.Flags().StringVar(&statusOpts.visibility, "visibility", "", "Visibility (direct|private|unlisted|public)")
 	tootAliasCmd.Flags().StringVar(&statusOpts.spoiler, "spoiler", "", "Spoiler warning (CW)")
 	tootAliasCmd.Flags().StringVar(&statusOpts.mediaIDs, "media-ids", "", "Comma-separated list of media IDs")
-	tootAliasCmd.Flags().StringVarP(&statusOpts.mediaFilePath, "file", "f", "", "Media attachment file name")
+	tootAliasCmd.Flags().StringArrayVarP(&statusOpts.mediaFilePaths, "file", "f", nil, "Media attachment file name (can be repeated)")
 	tootAliasCmd.Flags().StringVar(&statusOpts.textFilePath, "text-file", "", "Text file name (message content)")
 	tootAliasCmd.Flags().StringVarP(&statusOpts.inReplyToID, "in-reply-to", "r", "", "Status ID to reply to")
 	tootAliasCmd.Flags().BoolVar(&statusOpts.stdin, "stdin", false, "Read message content from standard input")
 	tootAliasCmd.Flags().BoolVar(&statusOpts.addMentions, "add-mentions", false, "Add mentions when replying")
 	tootAliasCmd.Flags().BoolVar(&statusOpts.sameVisibility, "same-visibility", false, "Use same visibility as original message (for replies)")
+	tootAliasCmd.Flags().DurationVar(&statusOpts.mediaTimeout, "media-timeout", defaultMediaTimeout, "Maximum time to wait for the media files to be processed (0 to disable)")
+	tootAliasCmd.Flags().StringVar(&statusOpts.idempotencyKey, "idempotency-key", "", "Idempotency key (avoid duplicates when a post is retried)")
+	tootAliasCmd.Flags().BoolVar(&statusOpts.retrySafe, "retry-safe", false, "Generate an idempotency key if none is provided")
+	tootAliasCmd.Flags().StringVar(&statusOpts.language, "language", "", "Status language (ISO 639 code)")
+	tootAliasCmd.Flags().BoolVar(&statusOpts.verifyLanguage, "verify-language", false, "Warn if the language of the posted status is not the requested one")
+	tootAliasCmd.Flags().StringVar(&statusOpts.replaceState, "replace-state", "", "State file: edit the status recorded in the file instead of posting a new one")
+	tootAliasCmd.Flags().BoolVar(&statusOpts.quiet, "quiet", false, "Do not display the URL of the new status on stderr")
+	tootAliasCmd.Flags().DurationVar(&statusOpts.deleteAfter, "delete-after", 0, "Schedule the deletion of the status (see 'status reap')")
 
 	// Flag completion
-	annotation := make(map[string][]string)
-	annotation[cobra.BashCompCustom] = []string{"__madonctl_visibility"}
-
-	tootAliasCmd.Flags().Lookup("visibility").Annotations = annotation
+	tootAliasCmd.RegisterFlagCompletionFunc("visibility", completeVisibility)
 
 	// This one will be used to check if the options were explicitly set or not
 	tootAliasFlags = tootAliasCmd.Flags()
@@ -53,13 +63,35 @@ var tootAliasCmd = &cobra.Command{
   madonctl toot --spoiler Warning "Hello, World"
   madonctl status post --media-ids ID1,ID2 "Here are the photos"
   madonctl post --sensitive --file image.jpg Image
+  echo "Look at this" | madonctl toot --stdin --file image.jpg
   madonctl toot --text-file message.txt
   madonctl toot --in-reply-to STATUSID "@user response"
   madonctl toot --in-reply-to STATUSID --add-mentions "response"
   echo "Hello from #madonctl" | madonctl toot --visibility unlisted --stdin
+  madonctl toot --idempotency-key 5d5ec5a4-3b3e-4b22-9d0f-3b1a0e6f1c7e "Hello"
+  madonctl toot --language fr --verify-language "Bonjour"
+  madonctl toot --delete-after 48h "This message will self-destruct"
+  madonctl toot --replace-state ~/.cache/dashboard.json "Service status: OK"
 
 The default visibility can be set in the configuration file with the option
-'default_visibility' (or with an environmnent variable).`,
+'default_visibility' (or with an environmnent variable).
+
+With --replace-state, the ID of the posted status is recorded in the given
+state file, and the next posts with the same state file edit this status
+(Mastodon 3.5+) instead of creating a new one.  If the recorded status has
+been deleted, a new status is posted.  The visibility and the parent status
+(--in-reply-to) of an edited status cannot be changed.
+
+The URL of the new status is displayed on the standard error output, unless
+the --quiet flag is used.
+
+The server will not create a new status if a post is sent again with the same
+idempotency key (e.g. after a timeout).  With --retry-safe, a random key is
+generated when --idempotency-key is not used; it is displayed in verbose mode.
+
+With --delete-after, the status is recorded in a local state file and will be
+deleted by the 'status reap' command once the delay has expired; this command
+has to be run periodically (e.g. from a cron job).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := madonInit(true); err != nil {
 			return err
@@ -70,6 +102,32 @@ The default visibility can be set in the configuration file with the option
 	},
 }
 
+// Maximum number of media attachments in a status
+const maxMediaAttachments = 4
+
+// readStatusText returns the status message text, from the command line
+// arguments, the text file (--text-file) or the standard input (--stdin)
+func readStatusText(args []string, stdin io.Reader) (string, error) {
+	opt := statusOpts
+
+	if opt.textFilePath != "" {
+		b, err := ioutil.ReadFile(opt.textFilePath)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	if opt.stdin {
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return "", errors.Wrap(err, "cannot read standard input")
+		}
+		// Remove the trailing newline (e.g. from echo)
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	return strings.Join(args, " "), nil
+}
+
 func toot(tootText string) (*madon.Status, error) {
 	opt := statusOpts
 
@@ -101,10 +159,14 @@ func toot(tootText string) (*madon.Status, error) {
 		return nil, errors.New("cannot parse media IDs")
 	}
 
-	if tootText == "" && len(ids) == 0 && opt.spoiler == "" && opt.mediaFilePath == "" {
+	if strings.TrimSpace(tootText) == "" && len(ids) == 0 && opt.spoiler == "" && len(opt.mediaFilePaths) == 0 {
 		return nil, errors.New("toot is empty")
 	}
 
+	if len(ids)+len(opt.mediaFilePaths) > maxMediaAttachments {
+		return nil, errors.New("too many media attachments")
+	}
+
 	if opt.inReplyToID != "" {
 		var initialStatus *madon.Status
 		var preserveVis bool
@@ -140,30 +202,110 @@ func toot(tootText string) (*madon.Status, error) {
 		}
 	}
 
-	// Uploading media file last
-	if opt.mediaFilePath != "" {
-		if len(ids) > 3 {
-			return nil, errors.New("too many media attachments")
-		}
-
-		fileMediaID, err := uploadFile(opt.mediaFilePath)
+	// Uploading media files last
+	for _, filePath := range opt.mediaFilePaths {
+		fileMediaID, err := uploadFile(filePath, opt.mediaTimeout)
 		if err != nil {
-			return nil, errors.Wrap(err, "cannot attach media file")
+			return nil, errors.Wrapf(err, "cannot attach media file '%s'", filePath)
 		}
 		if fileMediaID != "" {
 			ids = append(ids, fileMediaID)
 		}
 	}
 
-	postParam := madon.PostStatusParams{
-		Text:        tootText,
-		InReplyTo:   opt.inReplyToID,
-		MediaIDs:    ids,
-		Sensitive:   opt.sensitive,
-		SpoilerText: opt.spoiler,
-		Visibility:  opt.visibility,
+	if opt.idempotencyKey == "" && opt.retrySafe {
+		if opt.idempotencyKey, err = newUUID(); err != nil {
+			return nil, errors.Wrap(err, "cannot generate idempotency key")
+		}
+		if verbose {
+			errPrint("Idempotency key: %s", opt.idempotencyKey)
+		}
+	}
+
+	postParam := madonext.PostStatusParams{
+		PostStatusParams: madon.PostStatusParams{
+			Text:        tootText,
+			InReplyTo:   opt.inReplyToID,
+			MediaIDs:    ids,
+			Sensitive:   opt.sensitive,
+			SpoilerText: opt.spoiler,
+			Visibility:  opt.visibility,
+		},
+		IdempotencyKey: opt.idempotencyKey,
+		Language:       opt.language,
+	}
+	if opt.replaceState != "" {
+		return replaceStatus(opt.replaceState, postParam)
+	}
+	return gClient.PostStatusWithOptions(postParam)
+}
+
+// replaceState is the content of the --replace-state file
+type replaceState struct {
+	StatusID  madon.ActivityID `json:"status_id"`
+	Instance  string           `json:"instance"`
+	AccountID madon.ActivityID `json:"account_id"`
+}
+
+// replaceStatus edits the status recorded in the state file, or posts a new
+// status and records its ID if there is no such status
+func replaceStatus(stateFile string, postParam madonext.PostStatusParams) (*madon.Status, error) {
+	var state replaceState
+	data, err := ioutil.ReadFile(stateFile)
+	if err == nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse state file '%s'", stateFile)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "cannot read state file")
+	}
+
+	if state.StatusID != "" && state.Instance == gClient.InstanceURL {
+		// The statuses of other accounts cannot be edited
+		account, err := gClient.GetCurrentAccount()
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot check account details")
+		}
+		if state.AccountID == account.ID {
+			s, err := gClient.UpdateStatus(state.StatusID, postParam)
+			if err == nil {
+				checkEditedStatus(s, postParam)
+				return s, nil
+			}
+			if !madonext.IsNotFound(err) {
+				return nil, errors.Wrap(err, "cannot edit status")
+			}
+			if verbose {
+				errPrint("Status %s not found, posting a new status", state.StatusID)
+			}
+		} else if verbose {
+			errPrint("Status %s belongs to another account, posting a new status", state.StatusID)
+		}
+	}
+
+	s, err := gClient.PostStatusWithOptions(postParam)
+	if err != nil {
+		return nil, err
+	}
+	state = replaceState{StatusID: s.ID, Instance: gClient.InstanceURL}
+	if s.Account != nil {
+		state.AccountID = s.Account.ID
+	}
+	if err := writeStateFile(stateFile, state); err != nil {
+		errPrint("Warning: %s", err.Error())
+	}
+	return s, nil
+}
+
+// checkEditedStatus warns the user when the options that cannot be changed
+// when editing a status do not match the edited status s
+func checkEditedStatus(s *madon.Status, postParam madonext.PostStatusParams) {
+	if postParam.InReplyTo != "" && (s.InReplyToID == nil || *s.InReplyToID != postParam.InReplyTo) {
+		errPrint("Warning: --in-reply-to is ignored when editing a status")
+	}
+	if postParam.Visibility != "" && s.Visibility != postParam.Visibility {
+		errPrint("Warning: --visibility is ignored when editing a status (visibility: %s)", s.Visibility)
 	}
-	return gClient.PostStatus(postParam)
 }
 
 func mentionsList(s *madon.Status) (string, error) {
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+)
+
+// fakeStatusServer is a minimal API server for the media and status
+// endpoints.  The posted status parameters are stored in posted.
+func fakeStatusServer(t *testing.T, posted *map[string][]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/media", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+		_, _, err := r.FormFile("file")
+		assert.NoError(t, err)
+		json.NewEncoder(w).Encode(madon.Attachment{ID: "77", Type: "image", URL: "https://example.com/media/77.jpg"})
+	})
+	mux.HandleFunc("/api/v1/statuses", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		*posted = r.PostForm
+		json.NewEncoder(w).Encode(madon.Status{ID: "42", Content: r.PostForm.Get("status")})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestTootStdinWithFile(t *testing.T) {
+	var posted map[string][]string
+	ts := fakeStatusServer(t, &posted)
+	defer ts.Close()
+
+	gClient = madonext.NewClient(&madon.Client{
+		InstanceURL: ts.URL,
+		APIBase:     ts.URL + "/api",
+		UserToken:   &madon.UserToken{AccessToken: "token"},
+	})
+	defer func() { gClient = nil }()
+
+	dir, err := ioutil.TempDir("", "madonctl")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	imgFile := filepath.Join(dir, "img.jpg")
+	if !assert.NoError(t, ioutil.WriteFile(imgFile, []byte("fake image"), 0600)) {
+		return
+	}
+
+	// echo text | madonctl toot --stdin --file img.jpg
+	savedOpts := statusOpts
+	defer func() { statusOpts = savedOpts }()
+	statusOpts.stdin = true
+	statusOpts.mediaFilePaths = []string{imgFile}
+
+	text, err := readStatusText(nil, strings.NewReader("text\n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "text", text)
+
+	s, err := toot(text)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, madon.ActivityID("42"), s.ID)
+	assert.Equal(t, []string{"text"}, posted["status"])
+	assert.Equal(t, []string{"77"}, posted["media_ids[]"])
+}
+
+func TestTootEmptyStdinAndTooManyMedia(t *testing.T) {
+	var posted map[string][]string
+	ts := fakeStatusServer(t, &posted)
+	defer ts.Close()
+
+	gClient = madonext.NewClient(&madon.Client{
+		InstanceURL: ts.URL,
+		APIBase:     ts.URL + "/api",
+		UserToken:   &madon.UserToken{AccessToken: "token"},
+	})
+	defer func() { gClient = nil }()
+
+	savedOpts := statusOpts
+	defer func() { statusOpts = savedOpts }()
+	statusOpts.stdin = true
+
+	// Whitespace only, no media: the toot is empty
+	text, err := readStatusText(nil, strings.NewReader("\n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = toot(text)
+	assert.EqualError(t, err, "toot is empty")
+	assert.Nil(t, posted)
+
+	// Too many attachments
+	statusOpts.mediaIDs = "1,2,3,4"
+	statusOpts.mediaFilePaths = []string{"img.jpg"}
+	_, err = toot("text")
+	assert.EqualError(t, err, "too many media attachments")
+}
@@ -6,17 +6,25 @@
 package cmd
 
 import (
+	"bufio"
+	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/McKael/madon/v3"
 	"github.com/McKael/madonctl/printer"
 )
 
@@ -26,27 +34,43 @@ func checkOutputFormat(cmd *cobra.Command, args []string) error {
 		of = viper.GetString("default_output")
 	}
 	switch of {
-	case "", "plain", "json", "yaml", "template", "theme":
+	case "", "plain", "json", "json:compact", "yaml", "template", "theme", "rss":
 		return nil // Accepted
 	}
+	if outputFormat == "" {
+		return errors.Errorf("output format '%s' not supported (check default_output)", of)
+	}
 	return errors.Errorf("output format '%s' not supported", of)
 }
 
 // getOutputFormat return the requested output format, defaulting to "plain".
+//
+// The --output flag always takes precedence.  When it is not used, the
+// format is implied by the --json-compact, --template* and --theme flags,
+// and then by the default_output setting (from the environment or from the
+// configuration file).
 func getOutputFormat() string {
 	of := outputFormat
 	if of == "" {
-		of = viper.GetString("default_output")
+		switch {
+		case outputTemplate != "" || outputTemplateFile != "" || outputTemplatePreset != "":
+			of = "template"
+		case outputTheme != "":
+			of = "theme"
+		case jsonCompact:
+			of = "json"
+		default:
+			of = viper.GetString("default_output")
+		}
 		if of == "" {
 			of = "plain"
 		}
 	}
 
-	// Override format if a template or a theme is provided
-	if outputTemplate != "" || outputTemplateFile != "" {
-		of = "template"
-	} else if outputTheme != "" {
-		of = "theme"
+	// The JSON output is already compact (one object per line);
+	// "json:compact" and --json-compact are accepted for clarity.
+	if of == "json:compact" {
+		of = "json"
 	}
 	return of
 }
@@ -68,6 +92,8 @@ func getPrinter() (mcResourcePrinter, error) {
 	of := getOutputFormat()
 
 	// Initialize color mode
+	// In auto mode, the printers also check the NO_COLOR environment
+	// variable.
 	switch viper.GetString("color") {
 	case "on", "true", "yes", "force":
 		opt["color_mode"] = "on"
@@ -76,6 +102,13 @@ func getPrinter() (mcResourcePrinter, error) {
 	default:
 		opt["color_mode"] = "auto"
 	}
+	if noColor {
+		opt["color_mode"] = "off"
+	}
+
+	if outputWidth > 0 {
+		opt["width"] = strconv.Itoa(int(outputWidth))
+	}
 
 	if of == "theme" {
 		if outputTheme != "" {
@@ -84,10 +117,27 @@ func getPrinter() (mcResourcePrinter, error) {
 			opt["name"] = viper.GetString("default_theme")
 		}
 		opt["template_directory"] = viper.GetString("template_directory")
+	} else if of == "yaml" {
+		if yamlMultiDoc {
+			opt["multidoc"] = "true"
+		}
 	} else if of == "template" {
 		opt["template"] = outputTemplate
-		if outputTemplateFile != "" {
-			tmpl, err := readTemplate(outputTemplateFile, viper.GetString("template_directory"))
+		if outputTemplatePreset != "" {
+			tmpl, err := getTemplatePreset(outputTemplatePreset)
+			if err != nil {
+				return nil, err
+			}
+			opt["template"] = tmpl
+		} else if outputTemplateFile != "" ||
+			(strings.HasPrefix(outputTemplate, "@") && !strings.Contains(outputTemplate, "{{")) {
+			// --template @FILE is a shorthand for --template-file FILE
+			// (a template such as '@{{.acct}}' is not a file name)
+			tmplFile := outputTemplateFile
+			if tmplFile == "" {
+				tmplFile = outputTemplate[1:]
+			}
+			tmpl, err := readTemplate(tmplFile, viper.GetString("template_directory"))
 			if err != nil {
 				return nil, err
 			}
@@ -150,6 +200,37 @@ func errPrint(format string, a ...interface{}) (n int, err error) {
 }
 
 func (mcp *mcPrinter) printObj(obj interface{}) error {
+	if countOnly {
+		if v := reflect.ValueOf(obj); v.Kind() == reflect.Slice {
+			_, err := fmt.Println(v.Len())
+			return err
+		}
+	}
+
+	if v := reflect.ValueOf(obj); v.Kind() == reflect.Slice && v.Len() == 0 {
+		switch getOutputFormat() {
+		case "json", "yaml", "rss":
+			// Print an empty list, not null
+			obj = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+		default:
+			// Nothing would be displayed; tell the user if they are
+			// looking at the terminal.
+			if mcp.command == "" && isatty.IsTerminal(os.Stdout.Fd()) {
+				errPrint("(no results)")
+			}
+		}
+	}
+
+	if outputFields != "" {
+		switch getOutputFormat() {
+		case "json", "yaml":
+			var err error
+			if obj, err = projectFields(obj, outputFields); err != nil {
+				return err
+			}
+		}
+	}
+
 	if mcp.command == "" {
 		return mcp.PrintObj(obj, nil, "")
 	}
@@ -180,3 +261,219 @@ func (mcp *mcPrinter) printObj(obj interface{}) error {
 func (mcp *mcPrinter) setCommand(cmd string) {
 	mcp.command = cmd
 }
+
+// projectFields reduces the object (or each item of a list) to the fields
+// listed in the comma-separated list.  Fields are dotted paths using the
+// JSON field names, e.g. "id,account.acct,content".
+// Missing paths are omitted.
+func projectFields(obj interface{}, fieldList string) (interface{}, error) {
+	var paths [][]string
+	for _, f := range strings.Split(fieldList, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			paths = append(paths, strings.Split(f, "."))
+		}
+	}
+	if len(paths) == 0 {
+		return obj, nil
+	}
+
+	// Convert the object to generic maps
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	project := func(item interface{}) interface{} {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return item
+		}
+		out := make(map[string]interface{})
+		for _, path := range paths {
+			copyFieldPath(m, out, path)
+		}
+		return out
+	}
+
+	if list, ok := generic.([]interface{}); ok {
+		for i := range list {
+			list[i] = project(list[i])
+		}
+		return list, nil
+	}
+	return project(generic), nil
+}
+
+// copyFieldPath copies the value at path from src to dst, creating the
+// intermediate maps in dst as needed
+func copyFieldPath(src, dst map[string]interface{}, path []string) {
+	v, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = v
+		return
+	}
+	sub, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	dsub, ok := dst[path[0]].(map[string]interface{})
+	if !ok {
+		dsub = make(map[string]interface{})
+	}
+	copyFieldPath(sub, dsub, path[1:])
+	if len(dsub) > 0 {
+		dst[path[0]] = dsub
+	}
+}
+
+// capLimitParams applies the --max-results global limit to the limit
+// parameters, so that the pagination loops stop when enough items have
+// been fetched.  The results should still be truncated by the caller, since
+// the last page can exceed the limit.
+func capLimitParams(lopt *madon.LimitParams) *madon.LimitParams {
+	if maxResults == 0 || lopt == nil {
+		return lopt // No pagination loop
+	}
+	if lopt.All {
+		lopt.All = false
+		lopt.Limit = int(maxResults)
+	} else if lopt.Limit > int(maxResults) {
+		lopt.Limit = int(maxResults)
+	}
+	return lopt
+}
+
+// keepRange returns the bounds of the items to keep from a list of the given
+// length.  If keep is 0, the whole list is kept.  If tail is true, the last
+// keep items are kept instead of the first ones.
+func keepRange(length int, keep uint, tail bool) (int, int) {
+	if keep == 0 || length <= int(keep) {
+		return 0, length
+	}
+	if tail {
+		return length - int(keep), length
+	}
+	return 0, int(keep)
+}
+
+// parseTimeArg parses a date argument.
+// It can be an absolute date (RFC3339 timestamp, "YYYY-MM-DD hh:mm" or
+// "YYYY-MM-DD" in local time) or a duration before the current time
+// (Go duration string like "36h", or a number of days like "2d").
+func parseTimeArg(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	var d time.Duration
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseUint(strings.TrimSuffix(s, "d"), 10, 32)
+		if err != nil {
+			return time.Time{}, errors.Errorf("invalid date '%s'", s)
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil || d < 0 {
+			return time.Time{}, errors.Errorf("invalid date '%s'", s)
+		}
+	}
+	return time.Now().Add(-d), nil
+}
+
+// confirmAction asks the user to confirm an irreversible action on stderr,
+// unless yes is true.
+// In non-interactive contexts (when stdout is not a terminal), the action
+// has to be confirmed with the --yes flag.
+func confirmAction(prompt string, yes bool) error {
+	if yes {
+		return nil
+	}
+	if !isatty.IsTerminal(os.Stdout.Fd()) || !isatty.IsTerminal(os.Stdin.Fd()) {
+		return errors.New("confirmation required (use --yes)")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return errors.New("aborted")
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errors.New("aborted")
+}
+
+// writeStateFile saves the object v as JSON to the state file fileName
+// The file is written to a temporary file first, so that it is never left
+// truncated.
+func writeStateFile(fileName string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return errors.Wrap(err, "cannot create state file directory")
+	}
+	tmpFile := fileName + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, append(data, '\n'), 0600); err != nil {
+		return errors.Wrap(err, "cannot write state file")
+	}
+	return errors.Wrap(os.Rename(tmpFile, fileName), "cannot write state file")
+}
+
+// State file locking parameters
+const (
+	stateLockTimeout = 10 * time.Second
+	stateLockStale   = time.Minute
+)
+
+// lockStateFile takes an exclusive lock on the state file fileName, so that
+// concurrent madonctl processes do not lose each other's updates.
+// The returned function releases the lock.
+func lockStateFile(fileName string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return nil, errors.Wrap(err, "cannot create state file directory")
+	}
+	lockFile := fileName + ".lock"
+	deadline := time.Now().Add(stateLockTimeout)
+	for {
+		f, err := os.OpenFile(lockFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockFile) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.Wrap(err, "cannot lock state file")
+		}
+		// Remove the lock if it has been left by a dead process
+		if fi, err := os.Stat(lockFile); err == nil && time.Since(fi.ModTime()) > stateLockStale {
+			os.Remove(lockFile)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("state file is locked (lock file: '%s')", lockFile)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
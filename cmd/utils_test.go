@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputFormatPrecedence(t *testing.T) {
+	defer func(of, tmpl, theme string, jc bool) {
+		outputFormat, outputTemplate, outputTheme, jsonCompact = of, tmpl, theme, jc
+	}(outputFormat, outputTemplate, outputTheme, jsonCompact)
+
+	envKey := "MADONCTL_DEFAULT_OUTPUT"
+	defer os.Setenv(envKey, os.Getenv(envKey))
+	viper.SetConfigType("yaml")
+	defer viper.ReadConfig(strings.NewReader(""))
+
+	viper.SetEnvPrefix(AppName)
+	viper.AutomaticEnv()
+
+	tests := []struct {
+		name     string
+		flag     string // --output
+		config   string // default_output in the configuration file
+		env      string // MADONCTL_DEFAULT_OUTPUT
+		template string
+		theme    string
+		compact  bool
+		expected string
+	}{
+		{name: "default", expected: "plain"},
+		{name: "config", config: "json", expected: "json"},
+		{name: "env", env: "yaml", expected: "yaml"},
+		{name: "flag", flag: "yaml", expected: "yaml"},
+		{name: "flag over config", flag: "yaml", config: "json", expected: "yaml"},
+		{name: "flag over env", flag: "plain", env: "json", expected: "plain"},
+		{name: "flag over config and env", flag: "yaml", config: "json", env: "theme", expected: "yaml"},
+		{name: "env over config", config: "json", env: "yaml", expected: "yaml"},
+		{name: "flag json:compact", flag: "json:compact", config: "yaml", expected: "json"},
+		{name: "config json:compact", config: "json:compact", expected: "json"},
+		{name: "json-compact over config", config: "yaml", compact: true, expected: "json"},
+		{name: "flag over json-compact", flag: "yaml", compact: true, expected: "yaml"},
+		{name: "template over config", config: "json", template: "{{.id}}", expected: "template"},
+		{name: "theme over env", env: "json", theme: "ansi", expected: "theme"},
+		{name: "flag over template", flag: "json", template: "{{.id}}", expected: "json"},
+		{name: "flag over theme", flag: "yaml", config: "theme", theme: "ansi", expected: "yaml"},
+	}
+
+	for _, tt := range tests {
+		outputFormat = tt.flag
+		outputTemplate = tt.template
+		outputTheme = tt.theme
+		jsonCompact = tt.compact
+		cfg := ""
+		if tt.config != "" {
+			cfg = "default_output: " + tt.config + "\n"
+		}
+		if !assert.NoError(t, viper.ReadConfig(strings.NewReader(cfg))) {
+			return
+		}
+		os.Setenv(envKey, tt.env)
+
+		assert.Equal(t, tt.expected, getOutputFormat(), tt.name)
+		assert.NoError(t, checkOutputFormat(nil, nil), tt.name)
+	}
+}
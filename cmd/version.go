@@ -7,6 +7,7 @@ package cmd
 
 import (
 	"os"
+	"runtime"
 
 	"github.com/spf13/cobra"
 
@@ -19,28 +20,47 @@ import (
 type madonctlVersion struct {
 	AppName      string `json:"application_name"`
 	Version      string `json:"version"`
+	GitCommit    string `json:"git_commit,omitempty"`
+	BuildDate    string `json:"build_date,omitempty"`
+	GoVersion    string `json:"go_version"`
 	MadonVersion string `json:"madon_version"`
 }
 
 // VERSION of the madonctl application
 var VERSION = "3.0.0-dev"
 
+// Build information, set at link time, e.g.
+//
+//	go build -ldflags "-X github.com/McKael/madonctl/cmd.GitCommit=$(git rev-parse --short HEAD)"
+var (
+	GitCommit string // Git commit the binary was built from
+	BuildDate string // Date of the build
+)
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Display " + AppName + " version",
+	Example: `  madonctl version
+  madonctl version -o json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		const versionTemplate = `This is {{.application_name}} ` +
 			`version {{.version}} ` +
-			`(using madon library version {{.madon_version}}).{{"\n"}}`
+			`(using madon library version {{.madon_version}}).{{"\n"}}` +
+			`{{with .git_commit}}Git commit: {{.}}{{"\n"}}{{end}}` +
+			`{{with .build_date}}Build date: {{.}}{{"\n"}}{{end}}` +
+			`Go version: {{.go_version}}{{"\n"}}`
 		var v = madonctlVersion{
 			AppName:      AppName,
 			Version:      VERSION,
+			GitCommit:    GitCommit,
+			BuildDate:    BuildDate,
+			GoVersion:    runtime.Version(),
 			MadonVersion: madon.MadonVersion,
 		}
 		var p printer.ResourcePrinter
 		var err error
 		of := getOutputFormat()
-		if of == "template" {
+		if of != "plain" {
 			p, err = getPrinter()
 		} else { // Default
 			pOptions := printer.Options{"template": versionTemplate}
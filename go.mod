@@ -4,6 +4,7 @@ require (
 	github.com/McKael/madon/v3 v3.0.0-20230403195636-7e8193606782
 	github.com/ghodss/yaml v1.0.0
 	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/gorilla/websocket v1.5.0
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/kr/text v0.2.0
 	github.com/mattn/go-isatty v0.0.17
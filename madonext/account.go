@@ -0,0 +1,159 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/McKael/madon/v3"
+)
+
+// ErrAccountNotFound is returned by AccountLookup when no account matches
+// the given address
+var ErrAccountNotFound = errors.New("account not found")
+
+// AccountStatusesParams contains the filters for GetAccountStatusesFiltered
+type AccountStatusesParams struct {
+	OnlyPinned     bool   // Only statuses that have been pinned
+	OnlyMedia      bool   // Only statuses that have media attachments
+	ExcludeReplies bool   // Skip statuses that reply to other statuses
+	ExcludeReblogs bool   // Skip reblogs (boosts)
+	Tagged         string // Only statuses with this hashtag
+}
+
+// GetAccountStatusesFiltered returns a list of status entities for the
+// given account
+// This is similar to madon's GetAccountStatuses, with more filters.
+// If lopt.All is true, several requests will be made until the API server
+// has nothing to return.
+// If lopt.Limit is set (and not All), several queries can be made until the
+// limit is reached.
+func (mc *Client) GetAccountStatusesFiltered(accountID madon.ActivityID, filters AccountStatusesParams, lopt *madon.LimitParams) ([]madon.Status, error) {
+	if accountID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	params := url.Values{}
+	if filters.OnlyMedia {
+		params.Set("only_media", "true")
+	}
+	if filters.OnlyPinned {
+		params.Set("pinned", "true")
+	}
+	if filters.ExcludeReplies {
+		params.Set("exclude_replies", "true")
+	}
+	if filters.ExcludeReblogs {
+		params.Set("exclude_reblogs", "true")
+	}
+	if filters.Tagged != "" {
+		params.Set("tagged", filters.Tagged)
+	}
+
+	var statuses []madon.Status
+	endPoint := "v1/accounts/" + accountID + "/statuses"
+	if err := mc.getMultiple(endPoint, params, lopt, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// GetCurrentAccountStats returns the activity counters of the user account
+func (mc *Client) GetCurrentAccountStats() (*AccountStats, error) {
+	var stats AccountStats
+	if err := mc.apiCall("v1/accounts/verify_credentials", http.MethodGet, nil, nil, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// FollowAccountWithOptions follows an account
+// This is similar to madon's FollowAccount, with the notify option.
+// The reblogs and notify arguments can be nil to keep the server defaults.
+func (mc *Client) FollowAccountWithOptions(accountID madon.ActivityID, reblogs, notify *bool) (*madon.Relationship, error) {
+	if accountID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	params := url.Values{}
+	if reblogs != nil {
+		params.Set("reblogs", strconv.FormatBool(*reblogs))
+	}
+	if notify != nil {
+		params.Set("notify", strconv.FormatBool(*notify))
+	}
+
+	var rel madon.Relationship
+	endPoint := "v1/accounts/" + accountID + "/follow"
+	if err := mc.apiCall(endPoint, http.MethodPost, params, nil, nil, &rel); err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// MuteAccountWithDuration mutes an account
+// This is similar to madon's MuteAccount, with a mute duration (in seconds).
+// A zero duration means the mute is indefinite.
+func (mc *Client) MuteAccountWithDuration(accountID madon.ActivityID, muteNotifications *bool, duration int) (*madon.Relationship, error) {
+	if accountID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	params := url.Values{}
+	if muteNotifications != nil {
+		params.Set("notifications", strconv.FormatBool(*muteNotifications))
+	}
+	if duration > 0 {
+		params.Set("duration", strconv.Itoa(duration))
+	}
+
+	var rel madon.Relationship
+	endPoint := "v1/accounts/" + accountID + "/mute"
+	if err := mc.apiCall(endPoint, http.MethodPost, params, nil, nil, &rel); err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// AccountLookup returns the account with the given address (user for a local
+// account, or user@domain)
+// Unlike the search API, this endpoint only returns an exact match.
+// This requires Mastodon 3.4 or later.
+func (mc *Client) AccountLookup(acct string) (*madon.Account, error) {
+	if acct == "" {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	params := url.Values{}
+	params.Set("acct", acct)
+
+	var account madon.Account
+	if err := mc.apiCall("v1/accounts/lookup", http.MethodGet, params, nil, nil, &account); err != nil {
+		if IsNotFound(err) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// UpdateAccountDiscoverable sets whether the connected user account is
+// featured in the profile directory
+// This setting is not supported by madon's UpdateAccount.
+func (mc *Client) UpdateAccountDiscoverable(discoverable bool) (*madon.Account, error) {
+	params := url.Values{}
+	params.Set("discoverable", strconv.FormatBool(discoverable))
+
+	var account madon.Account
+	if err := mc.apiCall("v1/accounts/update_credentials", http.MethodPatch, params, nil, nil, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
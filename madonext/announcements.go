@@ -0,0 +1,60 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetAnnouncements returns the server announcements
+// If withDismissed is true, the announcements dismissed by the user are
+// included.
+func (mc *Client) GetAnnouncements(withDismissed bool) ([]Announcement, error) {
+	params := url.Values{}
+	if withDismissed {
+		params.Set("with_dismissed", "true")
+	}
+
+	var announcements []Announcement
+	if err := mc.apiCall("v1/announcements", http.MethodGet, params, nil, nil, &announcements); err != nil {
+		return nil, err
+	}
+	return announcements, nil
+}
+
+// DismissAnnouncement marks an announcement as read
+func (mc *Client) DismissAnnouncement(announcementID madon.ActivityID) error {
+	if announcementID == "" {
+		return madon.ErrInvalidID
+	}
+	endPoint := "v1/announcements/" + announcementID + "/dismiss"
+	return mc.apiCall(endPoint, http.MethodPost, nil, nil, nil, nil)
+}
+
+// AddAnnouncementReaction adds a reaction to an announcement
+// The emoji can be a unicode emoji or the shortcode of a custom emoji.
+func (mc *Client) AddAnnouncementReaction(announcementID madon.ActivityID, emoji string) error {
+	return mc.updateAnnouncementReaction(http.MethodPut, announcementID, emoji)
+}
+
+// RemoveAnnouncementReaction removes a reaction from an announcement
+func (mc *Client) RemoveAnnouncementReaction(announcementID madon.ActivityID, emoji string) error {
+	return mc.updateAnnouncementReaction(http.MethodDelete, announcementID, emoji)
+}
+
+func (mc *Client) updateAnnouncementReaction(method string, announcementID madon.ActivityID, emoji string) error {
+	if announcementID == "" {
+		return madon.ErrInvalidID
+	}
+	if emoji == "" {
+		return madon.ErrInvalidParameter
+	}
+	endPoint := "v1/announcements/" + announcementID + "/reactions/" + url.PathEscape(emoji)
+	return mc.apiCall(endPoint, method, nil, nil, nil, nil)
+}
@@ -0,0 +1,31 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetConversations returns the direct conversations of the user
+// If lopt.All is true, several requests will be made until the API server
+// has nothing to return.
+func (mc *Client) GetConversations(lopt *madon.LimitParams) ([]Conversation, error) {
+	var conversations []Conversation
+	if err := mc.getMultiple("v1/conversations", nil, lopt, &conversations); err != nil {
+		return nil, err
+	}
+	return conversations, nil
+}
+
+// DeleteConversation removes a conversation
+func (mc *Client) DeleteConversation(conversationID madon.ActivityID) error {
+	if conversationID == "" {
+		return madon.ErrInvalidID
+	}
+	return mc.apiCall("v1/conversations/"+conversationID, http.MethodDelete, nil, nil, nil, nil)
+}
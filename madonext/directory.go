@@ -0,0 +1,45 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetAccountDirectory returns accounts from the profile directory
+// The order can be "active" (recently active accounts first), "new" (newest
+// accounts first) or empty (server default).
+// If local is true, only local accounts are returned.
+// The offset and limit values are ignored if they are zero.
+func (mc *Client) GetAccountDirectory(order string, local bool, offset, limit int) ([]madon.Account, error) {
+	params := url.Values{}
+	switch order {
+	case "":
+	case "active", "new":
+		params.Set("order", order)
+	default:
+		return nil, madon.ErrInvalidParameter
+	}
+	if local {
+		params.Set("local", "true")
+	}
+	if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var accounts []madon.Account
+	if err := mc.apiCall("v1/directory", http.MethodGet, params, nil, nil, &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
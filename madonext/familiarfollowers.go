@@ -0,0 +1,35 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetFamiliarFollowers returns, for each of the accounts, the accounts
+// followed by the user that also follow them
+func (mc *Client) GetFamiliarFollowers(accountIDs []madon.ActivityID) ([]FamiliarFollowers, error) {
+	if len(accountIDs) < 1 {
+		return nil, madon.ErrInvalidID
+	}
+
+	params := url.Values{}
+	for _, id := range accountIDs {
+		if id == "" {
+			return nil, madon.ErrInvalidID
+		}
+		params.Add("id[]", id)
+	}
+
+	var ff []FamiliarFollowers
+	if err := mc.apiCall("v1/accounts/familiar_followers", http.MethodGet, params, nil, nil, &ff); err != nil {
+		return nil, err
+	}
+	return ff, nil
+}
@@ -0,0 +1,58 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetFeaturedTags returns the hashtags featured on the user's profile
+func (mc *Client) GetFeaturedTags() ([]FeaturedTag, error) {
+	var tags []FeaturedTag
+	if err := mc.apiCall("v1/featured_tags", http.MethodGet, nil, nil, nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetFeaturedTagSuggestions returns the user's most used hashtags that
+// could be featured on the profile
+func (mc *Client) GetFeaturedTagSuggestions() ([]madon.Tag, error) {
+	var tags []madon.Tag
+	if err := mc.apiCall("v1/featured_tags/suggestions", http.MethodGet, nil, nil, nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// AddFeaturedTag features a hashtag on the user's profile
+// The leading '#' is optional.
+func (mc *Client) AddFeaturedTag(name string) (*FeaturedTag, error) {
+	name = strings.TrimPrefix(name, "#")
+	if name == "" {
+		return nil, madon.ErrInvalidParameter
+	}
+	params := url.Values{}
+	params.Set("name", name)
+
+	var tag FeaturedTag
+	if err := mc.apiCall("v1/featured_tags", http.MethodPost, params, nil, nil, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// RemoveFeaturedTag stops featuring a hashtag on the user's profile
+func (mc *Client) RemoveFeaturedTag(featuredTagID madon.ActivityID) error {
+	if featuredTagID == "" {
+		return madon.ErrInvalidID
+	}
+	return mc.apiCall("v1/featured_tags/"+featuredTagID, http.MethodDelete, nil, nil, nil, nil)
+}
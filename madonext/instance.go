@@ -0,0 +1,30 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+)
+
+// GetInstanceV2 returns the current instance information (v2 API)
+// The v2 endpoint is available since Mastodon 4.0.
+func (mc *Client) GetInstanceV2() (*InstanceV2, error) {
+	var i InstanceV2
+	if err := mc.apiCall("v2/instance", http.MethodGet, nil, nil, nil, &i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// GetInstanceRules returns the rules of the current instance
+// This endpoint does not require authentication.
+func (mc *Client) GetInstanceRules() ([]Rule, error) {
+	var rules []Rule
+	if err := mc.apiCall("v1/instance/rules", http.MethodGet, nil, nil, nil, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,67 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/McKael/madon/v3"
+)
+
+// ListParams contains the list settings for CreateListWithParams and
+// UpdateListWithParams
+// Empty values (or nil pointers) are not sent to the server.
+type ListParams struct {
+	Title         string
+	RepliesPolicy string // followed, list or none
+	Exclusive     *bool  // Hide list members from the home timeline
+}
+
+// CreateListWithParams creates a List
+// This is similar to madon's CreateList, with the Mastodon 4 settings.
+func (mc *Client) CreateListWithParams(lp ListParams) (*List, error) {
+	if lp.Title == "" {
+		return nil, errors.New("missing list title")
+	}
+	return mc.setSingleList(http.MethodPost, "", lp)
+}
+
+// UpdateListWithParams updates an existing List
+// This is similar to madon's UpdateList, with the Mastodon 4 settings.
+func (mc *Client) UpdateListWithParams(listID madon.ActivityID, lp ListParams) (*List, error) {
+	if listID == "" {
+		return nil, errors.New("invalid list ID")
+	}
+	return mc.setSingleList(http.MethodPut, listID, lp)
+}
+
+func (mc *Client) setSingleList(method string, listID madon.ActivityID, lp ListParams) (*List, error) {
+	endPoint := "v1/lists"
+	if listID != "" {
+		endPoint += "/" + listID
+	}
+
+	params := url.Values{}
+	if lp.Title != "" {
+		params.Set("title", lp.Title)
+	}
+	if lp.RepliesPolicy != "" {
+		params.Set("replies_policy", lp.RepliesPolicy)
+	}
+	if lp.Exclusive != nil {
+		params.Set("exclusive", strconv.FormatBool(*lp.Exclusive))
+	}
+
+	var list List
+	if err := mc.apiCall(endPoint, method, params, nil, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
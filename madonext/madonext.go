@@ -0,0 +1,233 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+// Package madonext provides Mastodon API calls and entities that are not
+// (yet) available in the madon library.
+package madonext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/McKael/madon/v3"
+)
+
+// Client is a madon client with a few extra API calls
+type Client struct {
+	*madon.Client
+}
+
+// NewClient returns a Client wrapping the madon client mc
+// Note: the default HTTP client transport is wrapped so that the pagination
+// links of the API responses can be retrieved with LastCursors.
+func NewClient(mc *madon.Client) *Client {
+	installRecorder()
+	return &Client{Client: mc}
+}
+
+type apiLinks struct {
+	next, prev *madon.LimitParams
+}
+
+var linkRegex = regexp.MustCompile(`<([^>]+)>; rel="([^"]+)`)
+
+// parseLink decodes the pagination links from the Link HTTP header
+func parseLink(links []string) (*apiLinks, error) {
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	al := new(apiLinks)
+	for _, l := range links {
+		for _, submatch := range linkRegex.FindAllStringSubmatch(l, -1) {
+			if len(submatch) != 3 {
+				continue
+			}
+			u, err := url.Parse(submatch[1])
+			if err != nil {
+				return al, err
+			}
+			since := u.Query().Get("since_id")
+			max := u.Query().Get("max_id")
+			if since == "" && max == "" {
+				continue
+			}
+			lp := &madon.LimitParams{SinceID: since, MaxID: max}
+			if lim := u.Query().Get("limit"); lim != "" {
+				if lp.Limit, err = strconv.Atoi(lim); err != nil {
+					return al, err
+				}
+			}
+			switch submatch[2] {
+			case "prev":
+				al.prev = lp
+			case "next":
+				al.next = lp
+			}
+		}
+	}
+	return al, nil
+}
+
+// apiCall makes a call to the Mastodon API server
+// The endPoint is relative to the API base URL (e.g. "v1/directory").
+// If links is not nil, the prev/next links from the API response headers
+// will be set (if they exist) in the structure.
+// If data is nil, the response body is ignored.
+func (mc *Client) apiCall(endPoint, method string, params url.Values, lopt *madon.LimitParams, links *apiLinks, data interface{}) error {
+	return mc.apiCallWithHeaders(endPoint, method, params, nil, lopt, links, data)
+}
+
+// apiCallWithHeaders is like apiCall, with extra HTTP request headers
+func (mc *Client) apiCallWithHeaders(endPoint, method string, params url.Values, headers http.Header, lopt *madon.LimitParams, links *apiLinks, data interface{}) error {
+	if mc == nil || mc.Client == nil {
+		return madon.ErrUninitializedClient
+	}
+
+	// Do not modify the caller's parameters
+	p := url.Values{}
+	for k, v := range params {
+		p[k] = v
+	}
+	if lopt != nil {
+		if lopt.Limit > 0 {
+			p.Set("limit", strconv.Itoa(lopt.Limit))
+		}
+		if lopt.SinceID != "" {
+			p.Set("since_id", lopt.SinceID)
+		}
+		if lopt.MaxID != "" {
+			p.Set("max_id", lopt.MaxID)
+		}
+	}
+
+	target := mc.APIBase + "/" + endPoint
+	var body []byte
+	if method == http.MethodGet {
+		if len(p) > 0 {
+			target += "?" + p.Encode()
+		}
+	} else if len(p) > 0 {
+		body = []byte(p.Encode())
+	}
+
+	req, err := mc.newRequest(method, target, body)
+	if err != nil {
+		return err
+	}
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for k, v := range headers {
+		req.Header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	_, err = mc.sendRequest(req, endPoint, links, data)
+	return err
+}
+
+// newRequest returns an API request with the authorization header
+func (mc *Client) newRequest(method, target string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(method, target, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", fmt.Sprintf("madon/%s", madon.MadonVersion))
+	if mc.UserToken != nil {
+		req.Header.Set("Authorization", "Bearer "+mc.UserToken.AccessToken)
+	}
+	return req, nil
+}
+
+// sendRequest sends the API request and decodes the response into data
+// (see apiCall).  It returns the HTTP status code of the response.
+func (mc *Client) sendRequest(req *http.Request, endPoint string, links *apiLinks, data interface{}) (int, error) {
+	method := req.Method
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "API query (%s) failed", endPoint)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		var mastodonError madon.Error
+		errorText := http.StatusText(res.StatusCode)
+		if json.NewDecoder(res.Body).Decode(&mastodonError) == nil && mastodonError.Text != "" {
+			errorText = mastodonError.Text
+		}
+		// The error string format is the same as the madon library's,
+		// so that callers can check the status code the same way.
+		return res.StatusCode, errors.Wrapf(errors.Errorf("bad server status code (%d): %s", res.StatusCode, errorText),
+			"API query (%s) failed", endPoint)
+	}
+
+	if links != nil {
+		pLinks, err := parseLink(res.Header["Link"])
+		if err != nil {
+			return res.StatusCode, errors.Wrapf(err, "cannot decode header links (%s)", method)
+		}
+		if pLinks != nil {
+			*links = *pLinks
+		}
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, errors.Wrapf(err, "cannot read API response (%s)", method)
+	}
+	if data == nil || len(bytes.TrimSpace(b)) == 0 {
+		return res.StatusCode, nil
+	}
+	if err := json.Unmarshal(b, data); err != nil {
+		return res.StatusCode, errors.Wrapf(err, "cannot decode API response (%s)", method)
+	}
+	return res.StatusCode, nil
+}
+
+// IsNotFound returns true if the error is an API 404 error
+// It works with the errors returned by both the madon library and madonext.
+func IsNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "status code (404)")
+}
+
+// getMultiple fetches a list of entities; data must be a pointer to a slice.
+// If lopt.All is true, several requests will be made until the API server
+// has nothing to return.
+// If lopt.Limit is set (and not All), several queries can be made until the
+// limit is reached.
+func (mc *Client) getMultiple(endPoint string, params url.Values, lopt *madon.LimitParams, data interface{}) error {
+	var links apiLinks
+	if err := mc.apiCall(endPoint, http.MethodGet, params, lopt, &links, data); err != nil {
+		return err
+	}
+	if lopt == nil {
+		return nil
+	}
+
+	list := reflect.ValueOf(data).Elem()
+	for (lopt.All || lopt.Limit > list.Len()) && links.next != nil {
+		newlopt := links.next
+		links = apiLinks{}
+		page := reflect.New(list.Type())
+		if err := mc.apiCall(endPoint, http.MethodGet, params, newlopt, &links, page.Interface()); err != nil {
+			return err
+		}
+		if page.Elem().Len() == 0 {
+			break
+		}
+		list.Set(reflect.AppendSlice(list, page.Elem()))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetAttachment returns a media attachment
+// The attachment must belong to the user and must not be attached to a
+// status yet.
+func (mc *Client) GetAttachment(mediaID madon.ActivityID) (*madon.Attachment, error) {
+	if mediaID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	var attachment madon.Attachment
+	endPoint := "v1/media/" + mediaID
+	if err := mc.apiCall(endPoint, http.MethodGet, nil, nil, nil, &attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// UploadMediaAsync uploads a media file with the asynchronous v2 API
+// (Mastodon 3.1.3+).  The description and focus arguments can be empty.
+// The processing return value is true if the server has not finished
+// processing the media yet (the attachment URL is not available); in this
+// case the attachment can be polled with GetAttachment.
+func (mc *Client) UploadMediaAsync(filePath, description, focus string) (attachment *madon.Attachment, processing bool, err error) {
+	if mc == nil || mc.Client == nil {
+		return nil, false, madon.ErrUninitializedClient
+	}
+	if filePath == "" {
+		return nil, false, madon.ErrInvalidParameter
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "cannot read file")
+	}
+	defer f.Close()
+
+	buf := bytes.Buffer{}
+	w := multipart.NewWriter(&buf)
+	fw, err := w.CreateFormFile("file", filepath.Base(f.Name()))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "media upload")
+	}
+	if _, err := io.Copy(fw, f); err != nil {
+		return nil, false, errors.Wrap(err, "media upload")
+	}
+	if description != "" {
+		if err := w.WriteField("description", description); err != nil {
+			return nil, false, errors.Wrap(err, "form field: description")
+		}
+	}
+	if focus != "" {
+		if err := w.WriteField("focus", focus); err != nil {
+			return nil, false, errors.Wrap(err, "form field: focus")
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, false, errors.Wrap(err, "media upload")
+	}
+
+	endPoint := "v2/media"
+	req, err := mc.newRequest(http.MethodPost, mc.APIBase+"/"+endPoint, buf.Bytes())
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	var a madon.Attachment
+	code, err := mc.sendRequest(req, endPoint, nil, &a)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "media upload failed")
+	}
+	if a.ID == "" {
+		return nil, false, errors.New("no attachment returned by the server")
+	}
+	// 202 Accepted: the media is still being processed
+	return &a, code == http.StatusAccepted || a.URL == "", nil
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetPoll returns a poll
+func (mc *Client) GetPoll(pollID madon.ActivityID) (*Poll, error) {
+	if pollID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	var poll Poll
+	if err := mc.apiCall("v1/polls/"+pollID, http.MethodGet, nil, nil, nil, &poll); err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// VotePoll votes in a poll
+// The choices are the indexes of the selected options (starting at 0).
+// The updated poll is returned.
+func (mc *Client) VotePoll(pollID madon.ActivityID, choices []int) (*Poll, error) {
+	if pollID == "" {
+		return nil, madon.ErrInvalidID
+	}
+	if len(choices) == 0 {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	params := url.Values{}
+	for _, c := range choices {
+		params.Add("choices[]", strconv.Itoa(c))
+	}
+
+	var poll Poll
+	if err := mc.apiCall("v1/polls/"+pollID+"/votes", http.MethodPost, params, nil, nil, &poll); err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
@@ -0,0 +1,19 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+)
+
+// GetPreferences returns the user preferences stored on the server
+func (mc *Client) GetPreferences() (*Preferences, error) {
+	var prefs Preferences
+	if err := mc.apiCall("v1/preferences", http.MethodGet, nil, nil, nil, &prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
@@ -0,0 +1,44 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/McKael/madon/v3"
+)
+
+// ReportAccount reports a user account to the moderators
+// Unlike madon's ReportUser, the status IDs and the comment are optional.
+// If forward is true, the report is also sent to the remote instance of
+// the account.
+func (mc *Client) ReportAccount(accountID madon.ActivityID, statusIDs []madon.ActivityID, comment string, forward bool) (*madon.Report, error) {
+	if accountID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	params := url.Values{}
+	params.Set("account_id", accountID)
+	for _, id := range statusIDs {
+		if id == "" {
+			return nil, madon.ErrInvalidID
+		}
+		params.Add("status_ids[]", id)
+	}
+	if comment != "" {
+		params.Set("comment", comment)
+	}
+	if forward {
+		params.Set("forward", "true")
+	}
+
+	var report madon.Report
+	if err := mc.apiCall("v1/reports", http.MethodPost, params, nil, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
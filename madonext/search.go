@@ -0,0 +1,56 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/McKael/madon/v3"
+)
+
+// SearchParams contains the options for SearchV2
+type SearchParams struct {
+	Type      string // accounts, hashtags or statuses (empty for all types)
+	Resolve   bool   // Resolve non-local accounts and statuses
+	Following bool   // Only accounts the user is following
+	Limit     int
+	Offset    int
+}
+
+// SearchV2 searches for contents (accounts, statuses or hashtags)
+// This is similar to madon's Search, with more options; it requires the v2
+// API.
+func (mc *Client) SearchV2(query string, sp SearchParams) (*madon.Results, error) {
+	if query == "" {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	if sp.Type != "" {
+		params.Set("type", sp.Type)
+	}
+	if sp.Resolve {
+		params.Set("resolve", "true")
+	}
+	if sp.Following {
+		params.Set("following", "true")
+	}
+	if sp.Limit > 0 {
+		params.Set("limit", strconv.Itoa(sp.Limit))
+	}
+	if sp.Offset > 0 {
+		params.Set("offset", strconv.Itoa(sp.Offset))
+	}
+
+	var results madon.Results
+	if err := mc.apiCall("v2/search", http.MethodGet, params, nil, nil, &results); err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
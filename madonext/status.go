@@ -0,0 +1,230 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/McKael/madon/v3"
+)
+
+// ReblogStatusWithVisibility reblogs a status with the given visibility
+// (public, unlisted or private).
+// If the visibility is empty, the server default is used.
+func (mc *Client) ReblogStatusWithVisibility(statusID madon.ActivityID, visibility string) (*madon.Status, error) {
+	if statusID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	params := url.Values{}
+	if visibility != "" {
+		params.Set("visibility", visibility)
+	}
+
+	var status madon.Status
+	endPoint := "v1/statuses/" + statusID + "/reblog"
+	if err := mc.apiCall(endPoint, http.MethodPost, params, nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// UnreblogStatus cancels the reblog of a status
+// Unlike madon's UnreblogStatus, it returns the updated (original) status.
+func (mc *Client) UnreblogStatus(statusID madon.ActivityID) (*madon.Status, error) {
+	return mc.updateStatus(statusID, "unreblog")
+}
+
+// UnfavouriteStatus removes a status from the favourites
+// Unlike madon's UnfavouriteStatus, it returns the updated status.
+func (mc *Client) UnfavouriteStatus(statusID madon.ActivityID) (*madon.Status, error) {
+	return mc.updateStatus(statusID, "unfavourite")
+}
+
+// updateStatus sends a status action request and returns the updated status
+func (mc *Client) updateStatus(statusID madon.ActivityID, action string) (*madon.Status, error) {
+	if statusID == "" {
+		return nil, madon.ErrInvalidID
+	}
+
+	var status madon.Status
+	endPoint := "v1/statuses/" + statusID + "/" + action
+	if err := mc.apiCall(endPoint, http.MethodPost, nil, nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// PostStatusParams contains the options for PostStatusWithOptions
+type PostStatusParams struct {
+	madon.PostStatusParams
+
+	// IdempotencyKey is sent in the Idempotency-Key header, so that
+	// the server does not create duplicates if the request is retried.
+	IdempotencyKey string
+
+	// Language is the ISO 639 language code of the status
+	Language string
+}
+
+// PostStatusWithOptions posts a new status
+// It is like madon's PostStatus, with a few extra parameters.
+func (mc *Client) PostStatusWithOptions(cmdParams PostStatusParams) (*madon.Status, error) {
+	if cmdParams.Text == "" && len(cmdParams.MediaIDs) == 0 {
+		return nil, madon.ErrInvalidParameter
+	}
+	switch cmdParams.Visibility {
+	case "", "direct", "private", "unlisted", "public":
+		// Okay
+	default:
+		return nil, madon.ErrInvalidParameter
+	}
+
+	params := url.Values{}
+	params.Set("status", cmdParams.Text)
+	if cmdParams.InReplyTo != "" {
+		params.Set("in_reply_to_id", cmdParams.InReplyTo)
+	}
+	for _, id := range cmdParams.MediaIDs {
+		if id == "" {
+			return nil, madon.ErrInvalidID
+		}
+		params.Add("media_ids[]", id)
+	}
+	if cmdParams.Sensitive {
+		params.Set("sensitive", "true")
+	}
+	if cmdParams.SpoilerText != "" {
+		params.Set("spoiler_text", cmdParams.SpoilerText)
+	}
+	if cmdParams.Visibility != "" {
+		params.Set("visibility", cmdParams.Visibility)
+	}
+	if cmdParams.Language != "" {
+		params.Set("language", cmdParams.Language)
+	}
+
+	var headers http.Header
+	if key := strings.TrimSpace(cmdParams.IdempotencyKey); key != "" {
+		headers = http.Header{"Idempotency-Key": []string{key}}
+	}
+
+	var status madon.Status
+	if err := mc.apiCallWithHeaders("v1/statuses", http.MethodPost, params, headers, nil, nil, &status); err != nil {
+		return nil, err
+	}
+	if status.ID == "" {
+		return nil, madon.ErrEntityNotFound
+	}
+	return &status, nil
+}
+
+// UpdateStatus edits an existing status (Mastodon 3.5+)
+// The text, the spoiler, the sensitive flag, the media attachments and the
+// language can be updated; the other parameters are ignored.
+func (mc *Client) UpdateStatus(statusID madon.ActivityID, cmdParams PostStatusParams) (*madon.Status, error) {
+	if statusID == "" {
+		return nil, madon.ErrInvalidID
+	}
+	if cmdParams.Text == "" && len(cmdParams.MediaIDs) == 0 {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	params := url.Values{}
+	params.Set("status", cmdParams.Text)
+	for _, id := range cmdParams.MediaIDs {
+		if id == "" {
+			return nil, madon.ErrInvalidID
+		}
+		params.Add("media_ids[]", id)
+	}
+	if cmdParams.Sensitive {
+		params.Set("sensitive", "true")
+	}
+	params.Set("spoiler_text", cmdParams.SpoilerText)
+	if cmdParams.Language != "" {
+		params.Set("language", cmdParams.Language)
+	}
+
+	var status madon.Status
+	if err := mc.apiCall("v1/statuses/"+statusID, http.MethodPut, params, nil, nil, &status); err != nil {
+		return nil, err
+	}
+	if status.ID == "" {
+		return nil, madon.ErrEntityNotFound
+	}
+	return &status, nil
+}
+
+// ErrReactionsNotSupported is returned when the server does not support
+// emoji reactions on statuses
+var ErrReactionsNotSupported = errors.New("emoji reactions are not supported by the server")
+
+// AddStatusReaction adds an emoji reaction to a status
+// The emoji can be a unicode emoji or the shortcode of a custom emoji.
+// The Glitch-soc and Akkoma (Pleroma) APIs are supported.
+func (mc *Client) AddStatusReaction(statusID madon.ActivityID, emoji string) (*ReactionStatus, error) {
+	return mc.updateStatusReaction(true, statusID, emoji)
+}
+
+// RemoveStatusReaction removes an emoji reaction from a status
+func (mc *Client) RemoveStatusReaction(statusID madon.ActivityID, emoji string) (*ReactionStatus, error) {
+	return mc.updateStatusReaction(false, statusID, emoji)
+}
+
+func (mc *Client) updateStatusReaction(add bool, statusID madon.ActivityID, emoji string) (*ReactionStatus, error) {
+	if statusID == "" {
+		return nil, madon.ErrInvalidID
+	}
+	if emoji == "" {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	var status struct {
+		ReactionStatus
+		// Akkoma/Pleroma reactions
+		Pleroma *struct {
+			EmojiReactions []Reaction `json:"emoji_reactions"`
+		} `json:"pleroma"`
+	}
+
+	// Glitch-soc API
+	op := "react"
+	if !add {
+		op = "unreact"
+	}
+	endPoint := "v1/statuses/" + statusID + "/" + op + "/" + url.PathEscape(emoji)
+	err := mc.apiCall(endPoint, http.MethodPost, nil, nil, nil, &status)
+
+	if IsNotFound(err) { // Akkoma/Pleroma API
+		method := http.MethodPut
+		if !add {
+			method = http.MethodDelete
+		}
+		endPoint = "v1/pleroma/statuses/" + statusID + "/reactions/" + url.PathEscape(emoji)
+		err = mc.apiCall(endPoint, method, nil, nil, nil, &status)
+	}
+
+	if IsNotFound(err) {
+		// Check the status exists before blaming the server
+		if _, err := mc.GetStatus(statusID); err != nil {
+			return nil, err
+		}
+		return nil, ErrReactionsNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(status.Reactions) == 0 && status.Pleroma != nil {
+		status.Reactions = status.Pleroma.EmojiReactions
+	}
+	return &status.ReactionStatus, nil
+}
@@ -0,0 +1,48 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetTag returns the hashtag information, including the follow state
+// The leading '#' is optional.
+func (mc *Client) GetTag(name string) (*Tag, error) {
+	return mc.tagCall(http.MethodGet, name, "")
+}
+
+// FollowTag follows a hashtag; its statuses will appear in the home timeline
+func (mc *Client) FollowTag(name string) (*Tag, error) {
+	return mc.tagCall(http.MethodPost, name, "follow")
+}
+
+// UnfollowTag stops following a hashtag
+func (mc *Client) UnfollowTag(name string) (*Tag, error) {
+	return mc.tagCall(http.MethodPost, name, "unfollow")
+}
+
+func (mc *Client) tagCall(method, name, op string) (*Tag, error) {
+	name = strings.TrimLeft(name, "#:")
+	if name == "" {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	endPoint := "v1/tags/" + url.PathEscape(name)
+	if op != "" {
+		endPoint += "/" + op
+	}
+
+	var tag Tag
+	if err := mc.apiCall(endPoint, method, nil, nil, nil, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
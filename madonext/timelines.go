@@ -0,0 +1,109 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/McKael/madon/v3"
+)
+
+// GetLinkTimeline returns the statuses discussing a trending link
+// (Mastodon 4.3+)
+// If lopt.All is true, several requests will be made until the API server
+// has nothing to return.
+// If minID is set, the oldest statuses newer than minID are returned (see
+// getTimelineStatuses).
+func (mc *Client) GetLinkTimeline(linkURL string, minID madon.ActivityID, lopt *madon.LimitParams) ([]madon.Status, error) {
+	if linkURL == "" {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	params := url.Values{}
+	params.Set("url", linkURL)
+
+	return mc.getTimelineStatuses("v1/timelines/link", params, minID, lopt)
+}
+
+// TimelineParams contains the options for GetTimeline
+type TimelineParams struct {
+	Local     bool             // Only local statuses (public timeline)
+	Remote    bool             // Only remote statuses (public timeline)
+	OnlyMedia bool             // Only statuses with media attachments
+	MinID     madon.ActivityID // Oldest statuses newer than this ID
+}
+
+// GetTimeline returns a timeline
+// This is similar to madon's GetTimelines, with a few extra parameters.
+// The timeline can be "home", "public", "direct", a hashtag (":TAG" or
+// "#TAG") or a list ("!ID").
+// If lopt.All is true, several requests will be made until the API server
+// has nothing to return.
+// If lopt.Limit is set (and not All), several queries can be made until the
+// limit is reached.
+func (mc *Client) GetTimeline(timeline string, tp TimelineParams, lopt *madon.LimitParams) ([]madon.Status, error) {
+	var endPoint string
+
+	switch {
+	case timeline == "home", timeline == "public", timeline == "direct":
+		endPoint = "v1/timelines/" + timeline
+	case strings.HasPrefix(timeline, ":"), strings.HasPrefix(timeline, "#"):
+		hashtag := timeline[1:]
+		if hashtag == "" {
+			return nil, errors.New("timelines API: empty hashtag")
+		}
+		endPoint = "v1/timelines/tag/" + url.PathEscape(hashtag)
+	case len(timeline) > 1 && strings.HasPrefix(timeline, "!"):
+		// Check the timeline is a number
+		for _, n := range timeline[1:] {
+			if n < '0' || n > '9' {
+				return nil, errors.New("timelines API: invalid list ID")
+			}
+		}
+		endPoint = "v1/timelines/list/" + timeline[1:]
+	default:
+		return nil, errors.New("GetTimeline: bad timelines argument")
+	}
+
+	if tp.Local && tp.Remote {
+		return nil, madon.ErrInvalidParameter
+	}
+
+	params := url.Values{}
+	if timeline == "public" && tp.Local {
+		params.Set("local", "true")
+	}
+	if timeline == "public" && tp.Remote {
+		params.Set("remote", "true")
+	}
+	if tp.OnlyMedia {
+		params.Set("only_media", "true")
+	}
+
+	return mc.getTimelineStatuses(endPoint, params, tp.MinID, lopt)
+}
+
+// getTimelineStatuses fetches the statuses of a timeline endpoint
+// If minID is set, a single page is fetched: the pagination links go
+// backward, i.e. to statuses older than minID.
+func (mc *Client) getTimelineStatuses(endPoint string, params url.Values, minID madon.ActivityID, lopt *madon.LimitParams) ([]madon.Status, error) {
+	var statuses []madon.Status
+	if minID != "" {
+		params.Set("min_id", minID)
+		if err := mc.apiCall(endPoint, http.MethodGet, params, lopt, nil, &statuses); err != nil {
+			return nil, err
+		}
+		return statuses, nil
+	}
+	if err := mc.getMultiple(endPoint, params, lopt, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
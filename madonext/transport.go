@@ -0,0 +1,117 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// responseRecorder is an HTTP transport that remembers the Link header
+// of the last response.  It can also override the User-Agent header.
+// The madon library uses the default HTTP client, so this is the only way
+// to get the pagination links of its API calls.
+type responseRecorder struct {
+	base http.RoundTripper
+
+	mu        sync.Mutex
+	lastLink  []string
+	userAgent string
+}
+
+var recorder = &responseRecorder{}
+var recorderOnce sync.Once
+
+// installRecorder sets up the response recorder in the default HTTP client
+func installRecorder() {
+	recorderOnce.Do(func() {
+		recorder.base = http.DefaultClient.Transport
+		http.DefaultClient.Transport = recorder
+	})
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (rr *responseRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := rr.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	rr.mu.Lock()
+	ua := rr.userAgent
+	rr.mu.Unlock()
+	if ua != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", ua)
+	}
+	res, err := base.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	rr.mu.Lock()
+	rr.lastLink = res.Header["Link"]
+	rr.mu.Unlock()
+	return res, nil
+}
+
+// SetUserAgent sets the User-Agent header of the requests made with the
+// default HTTP client.  An empty string restores the default value.
+func SetUserAgent(ua string) {
+	installRecorder()
+	recorder.mu.Lock()
+	recorder.userAgent = ua
+	recorder.mu.Unlock()
+}
+
+// Cursors contains the pagination parameters (max_id, since_id, min_id)
+// of the next and previous pages
+type Cursors struct {
+	Next, Prev url.Values
+}
+
+// LastCursors returns the pagination cursors from the Link header of the
+// last API response.  It returns nil if there was no Link header.
+func (mc *Client) LastCursors() *Cursors {
+	recorder.mu.Lock()
+	links := recorder.lastLink
+	recorder.mu.Unlock()
+
+	if len(links) == 0 {
+		return nil
+	}
+
+	c := &Cursors{}
+	for _, l := range links {
+		for _, submatch := range linkRegex.FindAllStringSubmatch(l, -1) {
+			if len(submatch) != 3 {
+				continue
+			}
+			u, err := url.Parse(submatch[1])
+			if err != nil {
+				continue
+			}
+			v := url.Values{}
+			for _, k := range []string{"max_id", "since_id", "min_id"} {
+				if id := u.Query().Get(k); id != "" {
+					v.Set(k, id)
+				}
+			}
+			if len(v) == 0 {
+				continue
+			}
+			switch submatch[2] {
+			case "next":
+				c.Next = v
+			case "prev":
+				c.Prev = v
+			}
+		}
+	}
+	if c.Next == nil && c.Prev == nil {
+		return nil
+	}
+	return c
+}
@@ -0,0 +1,249 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package madonext
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/McKael/madon/v3"
+)
+
+// AccountStats contains the activity counters of an account
+type AccountStats struct {
+	ID             madon.ActivityID `json:"id"`
+	Acct           string           `json:"acct"`
+	StatusesCount  int64            `json:"statuses_count"`
+	FollowersCount int64            `json:"followers_count"`
+	FollowingCount int64            `json:"following_count"`
+	LastStatusAt   *string          `json:"last_status_at"`
+}
+
+// Announcement represents a Mastodon announcement entity
+type Announcement struct {
+	ID          madon.ActivityID `json:"id"`
+	Content     string           `json:"content"`
+	StartsAt    *time.Time       `json:"starts_at"`
+	EndsAt      *time.Time       `json:"ends_at"`
+	AllDay      bool             `json:"all_day"`
+	PublishedAt time.Time        `json:"published_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	Read        bool             `json:"read"`
+	Mentions    []madon.Mention  `json:"mentions"`
+	Tags        []madon.Tag      `json:"tags"`
+	Emojis      []madon.Emoji    `json:"emojis"`
+	Reactions   []Reaction       `json:"reactions"`
+}
+
+// FamiliarFollowers contains the accounts followed by the user that also
+// follow the account with the given ID
+type FamiliarFollowers struct {
+	ID       madon.ActivityID `json:"id"`
+	Accounts []madon.Account  `json:"accounts"`
+}
+
+// FeaturedTag represents a Mastodon featured tag entity
+type FeaturedTag struct {
+	ID   madon.ActivityID `json:"id"`
+	Name string           `json:"name"`
+	URL  string           `json:"url"`
+	// Some server versions return the count as a string
+	StatusesCount json.Number `json:"statuses_count"`
+	LastStatusAt  *string     `json:"last_status_at"`
+}
+
+// InstanceV2 represents a Mastodon instance entity (v2 API)
+type InstanceV2 struct {
+	Domain        string                 `json:"domain"`
+	Title         string                 `json:"title"`
+	Version       string                 `json:"version"`
+	SourceURL     string                 `json:"source_url"`
+	Description   string                 `json:"description"`
+	Usage         InstanceUsage          `json:"usage"`
+	Thumbnail     InstanceThumbnail      `json:"thumbnail"`
+	Languages     []string               `json:"languages"`
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	Registrations InstanceRegistrations  `json:"registrations"`
+	Contact       InstanceContact        `json:"contact"`
+	Rules         []Rule                 `json:"rules"`
+}
+
+// InstanceUsage contains the usage statistics of an instance
+type InstanceUsage struct {
+	Users struct {
+		ActiveMonth int64 `json:"active_month"`
+	} `json:"users"`
+}
+
+// InstanceThumbnail contains the instance banner image
+// Versions contains the URLs of the image for the "@1x" and "@2x"
+// resolutions.
+type InstanceThumbnail struct {
+	URL      string            `json:"url"`
+	Blurhash string            `json:"blurhash,omitempty"`
+	Versions map[string]string `json:"versions,omitempty"`
+}
+
+// InstanceRegistrations contains the registration settings of an instance
+type InstanceRegistrations struct {
+	Enabled          bool    `json:"enabled"`
+	ApprovalRequired bool    `json:"approval_required"`
+	Message          *string `json:"message"`
+}
+
+// InstanceContact contains the contact information of an instance
+type InstanceContact struct {
+	Email   string         `json:"email"`
+	Account *madon.Account `json:"account"`
+}
+
+// List represents a Mastodon list entity
+// It contains the Mastodon 4 settings that are missing in madon's List.
+type List struct {
+	ID            madon.ActivityID `json:"id"`
+	Title         string           `json:"title"`
+	RepliesPolicy string           `json:"replies_policy,omitempty"`
+	Exclusive     bool             `json:"exclusive"`
+}
+
+// Poll represents a Mastodon poll entity
+type Poll struct {
+	ID          madon.ActivityID `json:"id"`
+	ExpiresAt   *time.Time       `json:"expires_at"`
+	Expired     bool             `json:"expired"`
+	Multiple    bool             `json:"multiple"`
+	VotesCount  int64            `json:"votes_count"`
+	VotersCount *int64           `json:"voters_count"`
+	Options     []PollOption     `json:"options"`
+	Emojis      []madon.Emoji    `json:"emojis"`
+	Voted       *bool            `json:"voted"`
+	OwnVotes    []int            `json:"own_votes"`
+}
+
+// PollOption represents a poll choice
+type PollOption struct {
+	Title      string `json:"title"`
+	VotesCount *int64 `json:"votes_count"`
+}
+
+// Preferences represents the user preferences stored on the server
+type Preferences struct {
+	PostingDefaultVisibility string  `json:"posting:default:visibility"`
+	PostingDefaultSensitive  bool    `json:"posting:default:sensitive"`
+	PostingDefaultLanguage   *string `json:"posting:default:language"`
+	ReadingExpandMedia       string  `json:"reading:expand:media"`
+	ReadingExpandSpoilers    bool    `json:"reading:expand:spoilers"`
+}
+
+// Reaction represents a Mastodon (announcement) reaction entity
+type Reaction struct {
+	Name      string `json:"name"`
+	Count     int64  `json:"count"`
+	Me        bool   `json:"me"`
+	URL       string `json:"url,omitempty"`
+	StaticURL string `json:"static_url,omitempty"`
+}
+
+// ReactionStatus is a status with its emoji reactions
+// Emoji reactions are not part of the Mastodon API; they are available
+// with some implementations (e.g. Glitch-soc, Akkoma).
+type ReactionStatus struct {
+	madon.Status
+	Reactions []Reaction `json:"reactions"`
+}
+
+// Rule represents a Mastodon server rule entity
+type Rule struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Hint string `json:"hint,omitempty"`
+}
+
+// StatusStats contains the counters of a status
+// This is a subset of the Status entity.
+type StatusStats struct {
+	ID              madon.ActivityID `json:"id"`
+	Visibility      string           `json:"visibility"`
+	RepliesCount    int64            `json:"replies_count"`
+	ReblogsCount    int64            `json:"reblogs_count"`
+	FavouritesCount int64            `json:"favourites_count"`
+}
+
+// NewStatusStats returns the counters of the status s
+func NewStatusStats(s *madon.Status) *StatusStats {
+	return &StatusStats{
+		ID:              s.ID,
+		Visibility:      s.Visibility,
+		RepliesCount:    s.RepliesCount,
+		ReblogsCount:    s.ReblogsCount,
+		FavouritesCount: s.FavouritesCount,
+	}
+}
+
+// ThreadStatus is a status of a conversation thread
+// Depth is the reply depth of the status in the thread (0 for the first
+// message).
+type ThreadStatus struct {
+	madon.Status
+	Depth int `json:"depth"`
+}
+
+// NewThread returns the statuses of the thread of the status s, i.e. its
+// ancestors, the status itself and its descendants, in chronological order.
+func NewThread(s *madon.Status, context *madon.Context) []ThreadStatus {
+	var statuses []madon.Status
+	if context != nil {
+		statuses = append(statuses, context.Ancestors...)
+	}
+	statuses = append(statuses, *s)
+	if context != nil {
+		statuses = append(statuses, context.Descendants...)
+	}
+
+	// The API returns the parent statuses before their replies
+	depth := make(map[madon.ActivityID]int)
+	thread := make([]ThreadStatus, len(statuses))
+	for i, st := range statuses {
+		d := 0
+		if st.InReplyToID != nil {
+			if pd, ok := depth[*st.InReplyToID]; ok {
+				d = pd + 1
+			}
+		}
+		depth[st.ID] = d
+		thread[i] = ThreadStatus{Status: st, Depth: d}
+	}
+
+	sort.SliceStable(thread, func(i, j int) bool {
+		return thread[i].CreatedAt.Before(thread[j].CreatedAt)
+	})
+	return thread
+}
+
+// Tag represents a Mastodon hashtag entity
+// It contains the follow state that is missing in madon's Tag.
+type Tag struct {
+	Name      string       `json:"name"`
+	URL       string       `json:"url"`
+	History   []TagHistory `json:"history"`
+	Following bool         `json:"following"`
+}
+
+// TagHistory represents the daily usage of a hashtag
+type TagHistory struct {
+	Day      madon.MastodonDate `json:"day"`
+	Uses     int64              `json:"uses,string"`
+	Accounts int64              `json:"accounts,string"`
+}
+
+// Conversation represents a Mastodon conversation entity
+type Conversation struct {
+	ID         madon.ActivityID `json:"id"`
+	Accounts   []madon.Account  `json:"accounts"`
+	Unread     bool             `json:"unread"`
+	LastStatus *madon.Status    `json:"last_status"`
+}
@@ -28,6 +28,8 @@ func (p *JSONPrinter) PrintObj(obj interface{}, w io.Writer, option string) erro
 		w = os.Stdout
 	}
 
+	// The output is not indented, so that every object fits on a
+	// single line.
 	jsonEncoder := json.NewEncoder(w)
 	//jsonEncoder.SetIndent("", "  ")
 	return jsonEncoder.Encode(obj)
@@ -10,9 +10,13 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
+	"github.com/McKael/madonctl/printer/colors"
 	"github.com/McKael/madonctl/printer/html2text"
 )
 
@@ -20,16 +24,26 @@ import (
 type PlainPrinter struct {
 	Indent      string
 	NoSubtitles bool
+	Colors      bool
 }
 
+// Regular expressions used to highlight status contents
+var (
+	plainHandleRE  = regexp.MustCompile(`(^|[^\w@/])(@\w+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+	plainHashtagRE = regexp.MustCompile(`(^|[^\w#/&])(#[\p{L}\p{N}_]+)`)
+)
+
 // NewPrinterPlain returns a plaintext ResourcePrinter
 // For PlainPrinter, the option parameter contains the indent prefix.
+// The "color_mode" option defines the color behaviour: it can be
+// "auto" (default), "on" (forced), "off" (disabled).
 func NewPrinterPlain(options Options) (*PlainPrinter, error) {
 	indentInc := "  "
 	if i, ok := options["indent"]; ok {
 		indentInc = i
 	}
-	return &PlainPrinter{Indent: indentInc}, nil
+	withColors := useColors(options["color_mode"])
+	return &PlainPrinter{Indent: indentInc, Colors: withColors}, nil
 }
 
 // PrintObj sends the object as text to the writer
@@ -45,7 +59,11 @@ func (p *PlainPrinter) PrintObj(obj interface{}, w io.Writer, initialIndent stri
 		[]madon.List, []madon.Mention, []madon.Notification,
 		[]madon.Relationship, []madon.Report, []madon.Results,
 		[]madon.Status, []madon.StreamEvent, []madon.Tag,
-		[]madon.WeekActivity, []madon.DomainName:
+		[]madon.WeekActivity, []madon.DomainName,
+		[]madonext.Announcement, []madonext.Conversation,
+		[]madonext.List, []madonext.FeaturedTag, []madonext.Tag,
+		[]madonext.FamiliarFollowers, []madonext.Rule,
+		[]madonext.ThreadStatus:
 		return p.plainForeach(o, w, initialIndent)
 	case *madon.DomainName:
 		return p.plainPrintDomainName(o, w, initialIndent)
@@ -111,10 +129,69 @@ func (p *PlainPrinter) PrintObj(obj interface{}, w io.Writer, initialIndent stri
 		return p.plainPrintWeekActivity(o, w, initialIndent)
 	case madon.WeekActivity:
 		return p.plainPrintWeekActivity(&o, w, initialIndent)
+	case *madonext.Announcement:
+		return p.plainPrintAnnouncement(o, w, initialIndent)
+	case madonext.Announcement:
+		return p.plainPrintAnnouncement(&o, w, initialIndent)
+	case *madonext.Conversation:
+		return p.plainPrintConversation(o, w, initialIndent)
+	case madonext.Conversation:
+		return p.plainPrintConversation(&o, w, initialIndent)
+	case *madon.Tag:
+		return p.plainPrintTag(o, w, initialIndent)
+	case madon.Tag:
+		return p.plainPrintTag(&o, w, initialIndent)
+	case *madonext.FeaturedTag:
+		return p.plainPrintFeaturedTag(o, w, initialIndent)
+	case madonext.FeaturedTag:
+		return p.plainPrintFeaturedTag(&o, w, initialIndent)
+	case *madonext.AccountStats:
+		return p.plainPrintAccountStats(o, w, initialIndent)
+	case madonext.AccountStats:
+		return p.plainPrintAccountStats(&o, w, initialIndent)
+	case *madonext.FamiliarFollowers:
+		return p.plainPrintFamiliarFollowers(o, w, initialIndent)
+	case madonext.FamiliarFollowers:
+		return p.plainPrintFamiliarFollowers(&o, w, initialIndent)
+	case *madonext.InstanceV2:
+		return p.plainPrintInstanceV2(o, w, initialIndent)
+	case madonext.InstanceV2:
+		return p.plainPrintInstanceV2(&o, w, initialIndent)
+	case *madonext.Poll:
+		return p.plainPrintPoll(o, w, initialIndent)
+	case madonext.Poll:
+		return p.plainPrintPoll(&o, w, initialIndent)
+	case *madonext.Preferences:
+		return p.plainPrintPreferences(o, w, initialIndent)
+	case madonext.Preferences:
+		return p.plainPrintPreferences(&o, w, initialIndent)
+	case *madonext.ReactionStatus:
+		return p.plainPrintReactionStatus(o, w, initialIndent)
+	case madonext.ReactionStatus:
+		return p.plainPrintReactionStatus(&o, w, initialIndent)
+	case *madonext.Rule:
+		return p.plainPrintRule(o, w, initialIndent)
+	case madonext.Rule:
+		return p.plainPrintRule(&o, w, initialIndent)
+	case *madonext.StatusStats:
+		return p.plainPrintStatusStats(o, w, initialIndent)
+	case madonext.StatusStats:
+		return p.plainPrintStatusStats(&o, w, initialIndent)
+	case *madonext.ThreadStatus:
+		return p.plainPrintStatus(&o.Status, w, initialIndent+strings.Repeat(p.Indent, o.Depth))
+	case madonext.ThreadStatus:
+		return p.plainPrintStatus(&o.Status, w, initialIndent+strings.Repeat(p.Indent, o.Depth))
+	case *madonext.Tag:
+		return p.plainPrintTagExt(o, w, initialIndent)
+	case madonext.Tag:
+		return p.plainPrintTagExt(&o, w, initialIndent)
+	case *madonext.List:
+		return p.plainPrintListExt(o, w, initialIndent)
+	case madonext.List:
+		return p.plainPrintListExt(&o, w, initialIndent)
 	}
 	// TODO: Mention
 	// TODO: StreamEvent
-	// TODO: Tag
 
 	return fmt.Errorf("PlainPrinter not yet implemented for %T (try json or yaml...)", obj)
 }
@@ -169,6 +246,28 @@ func indentedPrint(w io.Writer, indent string, title, skipIfEmpty bool, label st
 	fmt.Fprintf(w, "%s%s: %s\n", prefix, label, value)
 }
 
+// highlight returns the string with the requested ANSI foreground color
+// if colors are enabled.
+func (p *PlainPrinter) highlight(s string, color int) string {
+	if !p.Colors || s == "" {
+		return s
+	}
+	return colors.ANSICode(color, -1, -1) + s + colors.ANSICode(-1, -1, -1)
+}
+
+// highlightText colorizes the mentions and hashtags of a status text
+// if colors are enabled.
+func (p *PlainPrinter) highlightText(s string) string {
+	if !p.Colors {
+		return s
+	}
+	reset := colors.ANSICode(-1, -1, -1)
+	s = plainHandleRE.ReplaceAllString(s,
+		"${1}"+colors.ANSICode(colors.Cyan, -1, -1)+"${2}"+reset)
+	return plainHashtagRE.ReplaceAllString(s,
+		"${1}"+colors.ANSICode(colors.Yellow, -1, -1)+"${2}"+reset)
+}
+
 func (p *PlainPrinter) plainPrintDomainName(d *madon.DomainName, w io.Writer, indent string) error {
 	indentedPrint(w, indent, true, false, "Domain Name", "%s", string(*d))
 	return nil
@@ -217,6 +316,31 @@ func (p *PlainPrinter) plainPrintAccount(a *madon.Account, w io.Writer, indent s
 	return nil
 }
 
+func (p *PlainPrinter) plainPrintAccountStats(s *madonext.AccountStats, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Account ID", "%s", s.ID)
+	indentedPrint(w, indent, false, false, "User ID", "%s", s.Acct)
+	indentedPrint(w, indent, false, false, "Statuses count", "%d", s.StatusesCount)
+	indentedPrint(w, indent, false, false, "Followers count", "%d", s.FollowersCount)
+	indentedPrint(w, indent, false, false, "Following count", "%d", s.FollowingCount)
+	if s.LastStatusAt != nil {
+		indentedPrint(w, indent, false, true, "Last status", "%s", *s.LastStatusAt)
+	}
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintAnnouncement(a *madonext.Announcement, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Announcement ID", "%s", a.ID)
+	indentedPrint(w, indent, false, false, "Published", "%v", a.PublishedAt.Local())
+	indentedPrint(w, indent, false, false, "Read", "%v", a.Read)
+	indentedPrint(w, indent, false, false, "Contents", "%s", html2string(a.Content))
+	var reactions []string
+	for _, r := range a.Reactions {
+		reactions = append(reactions, fmt.Sprintf("%s %d", r.Name, r.Count))
+	}
+	indentedPrint(w, indent, false, true, "Reactions", "%s", strings.Join(reactions, ", "))
+	return nil
+}
+
 func (p *PlainPrinter) plainPrintAttachment(a *madon.Attachment, w io.Writer, indent string) error {
 	indentedPrint(w, indent, true, false, "Attachment ID", "%s", a.ID)
 	indentedPrint(w, indent, false, false, "Type", "%s", a.Type)
@@ -255,6 +379,20 @@ func (p *PlainPrinter) plainPrintContext(c *madon.Context, w io.Writer, indent s
 	return nil
 }
 
+func (p *PlainPrinter) plainPrintConversation(c *madonext.Conversation, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Conversation ID", "%s", c.ID)
+	var participants []string
+	for _, a := range c.Accounts {
+		participants = append(participants, "@"+a.Acct)
+	}
+	indentedPrint(w, indent, false, false, "Participants", "%s", strings.Join(participants, " "))
+	indentedPrint(w, indent, false, false, "Unread", "%v", c.Unread)
+	if c.LastStatus != nil {
+		p.plainPrintStatus(c.LastStatus, w, indent+p.Indent)
+	}
+	return nil
+}
+
 func (p *PlainPrinter) plainPrintEmoji(e *madon.Emoji, w io.Writer, indent string) error {
 	indentedPrint(w, indent, true, false, "Emoji shortcode", "%s", e.ShortCode)
 	indentedPrint(w, indent, false, false, "URL", "%s", e.URL)
@@ -276,6 +414,55 @@ func (p *PlainPrinter) plainPrintInstance(i *madon.Instance, w io.Writer, indent
 	return nil
 }
 
+func (p *PlainPrinter) plainPrintInstanceV2(i *madonext.InstanceV2, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Instance title", "%s", i.Title)
+	indentedPrint(w, indent, false, true, "Description", "%s", html2string(i.Description))
+	indentedPrint(w, indent, false, true, "Domain", "%s", i.Domain)
+	indentedPrint(w, indent, false, true, "Version", "%s", i.Version)
+	indentedPrint(w, indent, false, true, "Source URL", "%s", i.SourceURL)
+	indentedPrint(w, indent, false, false, "Monthly active users", "%d", i.Usage.Users.ActiveMonth)
+	if len(i.Languages) > 0 {
+		indentedPrint(w, indent, false, false, "Languages", "%s", strings.Join(i.Languages, ", "))
+	}
+	indentedPrint(w, indent, false, true, "Thumbnail", "%s", i.Thumbnail.URL)
+	indentedPrint(w, indent, false, false, "Registrations enabled", "%v", i.Registrations.Enabled)
+	indentedPrint(w, indent, false, false, "Approval required", "%v", i.Registrations.ApprovalRequired)
+	if i.Registrations.Message != nil {
+		indentedPrint(w, indent, false, true, "Registrations message", "%s", html2string(*i.Registrations.Message))
+	}
+	indentedPrint(w, indent, false, true, "Email", "%s", i.Contact.Email)
+	if c := i.Contact.Account; c != nil {
+		indentedPrint(w, indent+p.Indent, true, false, "Contact account ID", "%s (%s)", c.ID, c.Username)
+		indentedPrint(w, indent+p.Indent, false, false, "Contact user ID", "%s", c.Acct)
+		indentedPrint(w, indent+p.Indent, false, false, "Contact display name", "%s", c.DisplayName)
+	}
+	if len(i.Rules) > 0 {
+		indentedPrint(w, indent, false, false, "Rules", "%d", len(i.Rules))
+	}
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintFamiliarFollowers(f *madonext.FamiliarFollowers, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Account ID", "%s", f.ID)
+	indentedPrint(w, indent, false, false, "Familiar followers", "%d", len(f.Accounts))
+	for _, a := range f.Accounts {
+		indentedPrint(w, indent+p.Indent, true, false, "Account", "(%s) @%s - %s",
+			a.ID, a.Acct, a.DisplayName)
+	}
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintFeaturedTag(t *madonext.FeaturedTag, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Featured tag ID", "%s", t.ID)
+	indentedPrint(w, indent, false, false, "Name", "%s", t.Name)
+	indentedPrint(w, indent, false, true, "URL", "%s", t.URL)
+	indentedPrint(w, indent, false, true, "Statuses count", "%s", t.StatusesCount)
+	if t.LastStatusAt != nil {
+		indentedPrint(w, indent, false, true, "Last status", "%s", *t.LastStatusAt)
+	}
+	return nil
+}
+
 func (p *PlainPrinter) plainPrintInstancePeer(i *madon.InstancePeer, w io.Writer, indent string) error {
 	indentedPrint(w, indent, true, false, "Peer", "%s", *i)
 	return nil
@@ -287,8 +474,17 @@ func (p *PlainPrinter) plainPrintList(l *madon.List, w io.Writer, indent string)
 	return nil
 }
 
+func (p *PlainPrinter) plainPrintListExt(l *madonext.List, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "List ID", "%s", l.ID)
+	indentedPrint(w, indent, false, false, "Title", "%s", l.Title)
+	indentedPrint(w, indent, false, true, "Replies policy", "%s", l.RepliesPolicy)
+	indentedPrint(w, indent, false, false, "Exclusive", "%v", l.Exclusive)
+	return nil
+}
+
 func (p *PlainPrinter) plainPrintNotification(n *madon.Notification, w io.Writer, indent string) error {
 	indentedPrint(w, indent, true, false, "Notification ID", "%s", n.ID)
+	indentedPrint(w, indent, false, false, "Summary", "%s", p.notificationSummary(n))
 	indentedPrint(w, indent, false, false, "Type", "%s", n.Type)
 	indentedPrint(w, indent, false, false, "Timestamp", "%v", n.CreatedAt.Local())
 	if n.Account != nil {
@@ -301,6 +497,89 @@ func (p *PlainPrinter) plainPrintNotification(n *madon.Notification, w io.Writer
 	return nil
 }
 
+// notificationSummary returns a human-readable description of the
+// notification, e.g. "@bob favourited your status"
+func (p *PlainPrinter) notificationSummary(n *madon.Notification) string {
+	var action string
+	switch n.Type {
+	case "mention":
+		action = "mentioned you"
+	case "reblog":
+		action = "boosted your status"
+	case "favourite":
+		action = "favourited your status"
+	case "follow":
+		action = "followed you"
+	case "follow_request":
+		action = "requested to follow you"
+	case "poll":
+		action = "'s poll has ended"
+	case "status":
+		action = "posted a status"
+	case "update":
+		action = "edited a status"
+	case "admin.sign_up":
+		action = "signed up"
+	case "admin.report":
+		action = "filed a report"
+	default:
+		action = "sent a notification (" + n.Type + ")"
+	}
+
+	if n.Account == nil {
+		return action
+	}
+	author := p.highlight("@"+n.Account.Acct, colors.Cyan)
+	if strings.HasPrefix(action, "'") {
+		return author + action
+	}
+	return author + " " + action
+}
+
+func (p *PlainPrinter) plainPrintPoll(pl *madonext.Poll, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Poll ID", "%s", pl.ID)
+	if pl.ExpiresAt != nil {
+		indentedPrint(w, indent, false, false, "Expires", "%v", pl.ExpiresAt.Local())
+	}
+	indentedPrint(w, indent, false, false, "Expired", "%v", pl.Expired)
+	indentedPrint(w, indent, false, false, "Multiple choices", "%v", pl.Multiple)
+	indentedPrint(w, indent, false, false, "Votes", "%d", pl.VotesCount)
+	for i, o := range pl.Options {
+		votes := "?" // Hidden until the poll has ended
+		if o.VotesCount != nil {
+			votes = fmt.Sprintf("%d", *o.VotesCount)
+		}
+		mark := ""
+		for _, v := range pl.OwnVotes {
+			if v == i {
+				mark = " (voted)"
+			}
+		}
+		indentedPrint(w, indent, false, false, fmt.Sprintf("Option %d", i),
+			"%s [%s vote(s)]%s", o.Title, votes, mark)
+	}
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintRule(r *madonext.Rule, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Rule ID", "%s", r.ID)
+	indentedPrint(w, indent, false, false, "Text", "%s", r.Text)
+	indentedPrint(w, indent, false, true, "Hint", "%s", r.Hint)
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintPreferences(pr *madonext.Preferences, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Preferences", "")
+	indentedPrint(w, indent, false, false, "Default visibility", "%s", pr.PostingDefaultVisibility)
+	indentedPrint(w, indent, false, false, "Default sensitive", "%v", pr.PostingDefaultSensitive)
+	if pr.PostingDefaultLanguage != nil {
+		indentedPrint(w, indent, false, true, "Default language", "%s", *pr.PostingDefaultLanguage)
+	}
+	indentedPrint(w, indent, false, false, "Expand media", "%s", pr.ReadingExpandMedia)
+	indentedPrint(w, indent, false, false, "Expand spoilers", "%v", pr.ReadingExpandSpoilers)
+	return nil
+}
+
 func (p *PlainPrinter) plainPrintRelationship(r *madon.Relationship, w io.Writer, indent string) error {
 	indentedPrint(w, indent, true, false, "Account ID", "%s", r.ID)
 	indentedPrint(w, indent, false, false, "Following", "%v", r.Following)
@@ -348,7 +627,7 @@ func (p *PlainPrinter) plainPrintStatus(s *madon.Status, w io.Writer, indent str
 		if s.Account.DisplayName != "" {
 			author += " (" + s.Account.DisplayName + ")"
 		}
-		indentedPrint(w, indent, false, false, "From", "%s", author)
+		indentedPrint(w, indent, false, false, "From", "%s", p.highlight(author, colors.Cyan))
 	}
 	if s.Pinned {
 		indentedPrint(w, indent, false, false, "Pinned", "%v", s.Pinned)
@@ -369,7 +648,7 @@ func (p *PlainPrinter) plainPrintStatus(s *madon.Status, w io.Writer, indent str
 		indentedPrint(w, indent, false, false, "Sensitive (NSFW)", "%v", s.Sensitive)
 	}
 
-	indentedPrint(w, indent, false, false, "Contents", "%s", html2string(s.Content))
+	indentedPrint(w, indent, false, false, "Contents", "%s", p.highlightText(html2string(s.Content)))
 	if s.InReplyToID != nil && *s.InReplyToID != "" {
 		indentedPrint(w, indent, false, false, "In-Reply-To", "%s", *s.InReplyToID)
 	}
@@ -395,6 +674,52 @@ func (p *PlainPrinter) plainPrintStatus(s *madon.Status, w io.Writer, indent str
 	return nil
 }
 
+func (p *PlainPrinter) plainPrintReactionStatus(s *madonext.ReactionStatus, w io.Writer, indent string) error {
+	if err := p.plainPrintStatus(&s.Status, w, indent); err != nil {
+		return err
+	}
+	var reactions []string
+	for _, r := range s.Reactions {
+		reactions = append(reactions, fmt.Sprintf("%s %d", r.Name, r.Count))
+	}
+	indentedPrint(w, indent, false, true, "Reactions", "%s", strings.Join(reactions, ", "))
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintStatusStats(s *madonext.StatusStats, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Status ID", "%s", s.ID)
+	indentedPrint(w, indent, false, false, "Visibility", "%s", s.Visibility)
+	indentedPrint(w, indent, false, false, "Counts", "%d replies, %d reblogs, %d favourites",
+		s.RepliesCount, s.ReblogsCount, s.FavouritesCount)
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintTag(t *madon.Tag, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Tag", "%s", t.Name)
+	indentedPrint(w, indent, false, true, "URL", "%s", t.URL)
+	if len(t.History) > 0 {
+		var uses, accounts int64
+		for _, h := range t.History {
+			uses += h.Uses
+			accounts += h.Accounts
+		}
+		indentedPrint(w, indent, false, false, "Recent use", "%d use(s) by %d account(s) in %d day(s)",
+			uses, accounts, len(t.History))
+	}
+	return nil
+}
+
+func (p *PlainPrinter) plainPrintTagExt(t *madonext.Tag, w io.Writer, indent string) error {
+	indentedPrint(w, indent, true, false, "Tag", "%s", t.Name)
+	indentedPrint(w, indent, false, true, "URL", "%s", t.URL)
+	indentedPrint(w, indent, false, false, "Following", "%v", t.Following)
+	for _, h := range t.History {
+		indentedPrint(w, indent, false, false, ". Usage",
+			"%s: %d use(s) by %d account(s)", h.Day.Format("2006-01-02"), h.Uses, h.Accounts)
+	}
+	return nil
+}
+
 func (p *PlainPrinter) plainPrintUserToken(s *madon.UserToken, w io.Writer, indent string) error {
 	indentedPrint(w, indent, true, false, "User token", "%s", s.AccessToken)
 	indentedPrint(w, indent, false, true, "Type", "%s", s.TokenType)
@@ -8,6 +8,9 @@ package printer
 import (
 	"fmt"
 	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
 )
 
 // Options contains options used when creating a ResourcePrinter
@@ -37,6 +40,26 @@ func NewPrinter(output string, options Options) (ResourcePrinter, error) {
 		return NewPrinterTemplate(options)
 	case "theme":
 		return NewPrinterTheme(options)
+	case "rss":
+		return NewPrinterRSS(options)
 	}
 	return nil, fmt.Errorf("unhandled output format")
 }
+
+// useColors returns true if the output should be colorized, depending on the
+// color mode ("on", "off" or "auto")
+// In auto mode, colors are disabled when the standard output is not a
+// terminal or when the NO_COLOR environment variable is set (see
+// https://no-color.org/).
+func useColors(colorMode string) bool {
+	switch colorMode {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isatty.IsTerminal(os.Stdout.Fd())
+}
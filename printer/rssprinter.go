@@ -0,0 +1,178 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+package printer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/McKael/madon/v3"
+)
+
+// Maximum length of the item titles
+const rssTitleLength = 80
+
+// RSSPrinter represents an RSS 2.0 printer
+// It can only be used with statuses.
+type RSSPrinter struct {
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Generator   string    `xml:"generator"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// NewPrinterRSS returns an RSS ResourcePrinter
+func NewPrinterRSS(options Options) (*RSSPrinter, error) {
+	return &RSSPrinter{}, nil
+}
+
+// PrintObj sends the statuses as an RSS 2.0 document to the writer
+// If the writer w is nil, standard output will be used.
+// For RSSPrinter, the option parameter is currently not used.
+func (p *RSSPrinter) PrintObj(obj interface{}, w io.Writer, option string) error {
+	if w == nil {
+		w = os.Stdout
+	}
+
+	var sl []madon.Status
+	switch o := obj.(type) {
+	case []madon.Status:
+		sl = o
+	case madon.Status:
+		sl = []madon.Status{o}
+	case *madon.Status:
+		sl = []madon.Status{*o}
+	default:
+		return fmt.Errorf("RSSPrinter not implemented for %T (only statuses are supported)", obj)
+	}
+
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       "Mastodon statuses",
+			Description: "Mastodon statuses",
+			Generator:   "madonctl",
+		},
+	}
+
+	// Use the first account for the channel information
+	if len(sl) > 0 && sl[0].Account != nil {
+		a := sl[0].Account
+		doc.Channel.Title = "Statuses from @" + a.Acct
+		doc.Channel.Link = a.URL
+		if a.DisplayName != "" {
+			doc.Channel.Description = a.DisplayName + " (@" + a.Acct + ")"
+		} else {
+			doc.Channel.Description = "@" + a.Acct
+		}
+	}
+
+	for _, s := range sl {
+		doc.Channel.Items = append(doc.Channel.Items, rssStatusItem(&s))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// rssStatusItem returns the RSS item for the status s
+func rssStatusItem(s *madon.Status) rssItem {
+	item := rssItem{
+		GUID:    rssGUID{Value: s.URI},
+		PubDate: s.CreatedAt.UTC().Format(time.RFC1123Z),
+	}
+
+	// Boosts are displayed with the original status
+	content := s
+	prefix := ""
+	if s.Reblog != nil {
+		content = s.Reblog
+		if content.Account != nil {
+			prefix = "Boost of @" + content.Account.Acct + ": "
+		}
+	}
+	item.Link = content.URL
+
+	title := content.SpoilerText
+	if title == "" {
+		title = strings.Join(strings.Fields(html2string(content.Content)), " ")
+	}
+	if title == "" && len(content.MediaAttachments) > 0 {
+		title = fmt.Sprintf("(%d media attachment(s))", len(content.MediaAttachments))
+	}
+	item.Title = prefix + truncateRunes(title, rssTitleLength)
+
+	desc := content.Content
+	if content.SpoilerText != "" {
+		desc = "<p><strong>" + html.EscapeString(content.SpoilerText) + "</strong></p>" + desc
+	}
+	for _, a := range content.MediaAttachments {
+		u := a.URL
+		if u == "" && a.RemoteURL != nil {
+			u = *a.RemoteURL
+		}
+		if u == "" {
+			continue
+		}
+		u = html.EscapeString(u)
+		if a.Type == "image" {
+			alt := ""
+			if a.Description != nil {
+				alt = html.EscapeString(*a.Description)
+			}
+			desc += fmt.Sprintf(`<p><img src="%s" alt="%s"/></p>`, u, alt)
+		} else {
+			desc += fmt.Sprintf(`<p><a href="%s">%s</a></p>`, u, u)
+		}
+	}
+	item.Description = desc
+	return item
+}
+
+// truncateRunes truncates the string s to max characters
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
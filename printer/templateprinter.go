@@ -11,20 +11,25 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
+	"unicode/utf8"
 
 	"github.com/kr/text"
-	"github.com/mattn/go-isatty"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 	"github.com/McKael/madonctl/printer/colors"
 )
 
 // disableColors can be set to true to disable the color template function
 var disableColors bool
 
+// outputWidth overrides the terminal width when it is positive
+var outputWidth int
+
 // TemplatePrinter represents a Template printer
 type TemplatePrinter struct {
 	rawTemplate string
@@ -35,18 +40,22 @@ type TemplatePrinter struct {
 // For TemplatePrinter, the options parameter contains the template string.
 // The "color_mode" option defines the color behaviour: it can be
 // "auto" (default), "on" (forced), "off" (disabled).
+// The "width" option overrides the terminal width.
 func NewPrinterTemplate(options Options) (*TemplatePrinter, error) {
 	tmpl := options["template"]
 	if tmpl == "" {
 		return nil, fmt.Errorf("empty template")
 	}
 	t, err := template.New("output").Funcs(template.FuncMap{
-		"fromhtml": html2string,
-		"fromunix": unix2time,
-		"tolocal":  dateToLocal,
-		"color":    ansiColor,
-		"trim":     strings.TrimSpace,
-		"wrap":     wrap,
+		"fromhtml":  html2string,
+		"fromunix":  unix2time,
+		"tolocal":   dateToLocal,
+		"color":     ansiColor,
+		"trim":      strings.TrimSpace,
+		"wrap":      wrap,
+		"oneline":   oneline,
+		"truncate":  truncate,
+		"termwidth": terminalWidth,
 	}).Parse(tmpl)
 	if err != nil {
 		return nil, err
@@ -54,11 +63,14 @@ func NewPrinterTemplate(options Options) (*TemplatePrinter, error) {
 
 	// Update disableColors.
 	// In auto-mode, check if stdout is a TTY.
-	colorMode := options["color_mode"]
-	if colorMode == "off" || (colorMode != "on" && !isatty.IsTerminal(os.Stdout.Fd())) {
+	if !useColors(options["color_mode"]) {
 		disableColors = true
 	}
 
+	if ow, err := strconv.Atoi(options["width"]); err == nil && ow > 0 {
+		outputWidth = ow
+	}
+
 	return &TemplatePrinter{
 		rawTemplate: tmpl,
 		template:    t,
@@ -82,7 +94,11 @@ func (p *TemplatePrinter) PrintObj(obj interface{}, w io.Writer, tmpl string) er
 		[]madon.Instance, []madon.List, []madon.Mention,
 		[]madon.Notification, []madon.Relationship, []madon.Report,
 		[]madon.Results, []madon.Status, []madon.StreamEvent,
-		[]madon.Tag, []string:
+		[]madon.Tag, []string,
+		[]madonext.Announcement, []madonext.Conversation,
+		[]madonext.List, []madonext.FeaturedTag, []madonext.Tag,
+		[]madonext.FamiliarFollowers, []madonext.Rule,
+		[]madonext.ThreadStatus:
 		return p.templateForeach(ot, w)
 	}
 
@@ -167,6 +183,44 @@ func dateToLocal(s string) (time.Time, error) {
 	return t.Local(), err
 }
 
+// defaultTermWidth is the width used when the terminal size is unknown
+const defaultTermWidth = 80
+
+// terminalWidth returns the number of columns of the output terminal,
+// or defaultTermWidth if it cannot be determined.
+// The width can be overridden with the "width" option.
+func terminalWidth() int {
+	if outputWidth > 0 {
+		return outputWidth
+	}
+	if c := ttyColumns(); c > 0 {
+		return c
+	}
+	return defaultTermWidth
+}
+
+// oneline replaces the sequences of blank characters (including newlines)
+// with a single space.
+func oneline(txt string) string {
+	return strings.Join(strings.Fields(txt), " ")
+}
+
+// truncate cuts the text so that it does not exceed width characters.
+// If width is zero or negative, it is relative to the terminal width.
+func truncate(width int, txt string) string {
+	if width <= 0 {
+		width += terminalWidth()
+	}
+	if width < 1 {
+		width = 1
+	}
+	if utf8.RuneCountInString(txt) <= width {
+		return txt
+	}
+	r := []rune(txt)
+	return string(r[:width-1]) + "…"
+}
+
 // Wrap text with indent prefix
 func wrap(indent string, lineLength int, txt string) string {
 	width := lineLength - len(indent)
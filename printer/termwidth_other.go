@@ -0,0 +1,15 @@
+// Copyright © 2023 Mikael Berthe <mikael@lilotux.net>
+//
+// Licensed under the MIT license.
+// Please see the LICENSE file is this directory.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package printer
+
+// ttyColumns returns 0 since the terminal size is not available on this
+// platform.
+func ttyColumns() int {
+	return 0
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/McKael/madon/v3"
+	"github.com/McKael/madonctl/madonext"
 )
 
 const themeDirName = "themes"
@@ -25,6 +26,7 @@ type ThemePrinter struct {
 	name        string
 	templateDir string
 	colorMode   string
+	width       string
 }
 
 // NewPrinterTheme returns a Theme ResourcePrinter
@@ -46,6 +48,7 @@ func NewPrinterTheme(options Options) (*ThemePrinter, error) {
 		name:        name,
 		templateDir: options["template_directory"],
 		colorMode:   options["color_mode"],
+		width:       options["width"],
 	}, nil
 }
 
@@ -79,7 +82,8 @@ func (p *ThemePrinter) PrintObj(obj interface{}, w io.Writer, tmpl string) error
 		objType = "emoji"
 	case []madon.Instance, madon.Instance, *madon.Instance:
 		objType = "instance"
-	case []madon.List, madon.List, *madon.List:
+	case []madon.List, madon.List, *madon.List,
+		[]madonext.List, madonext.List, *madonext.List:
 		objType = "list"
 	case []madon.Mention, madon.Mention, *madon.Mention:
 		objType = "mention"
@@ -91,12 +95,31 @@ func (p *ThemePrinter) PrintObj(obj interface{}, w io.Writer, tmpl string) error
 		objType = "report"
 	case []madon.Results, madon.Results, *madon.Results:
 		objType = "results"
-	case []madon.Status, madon.Status, *madon.Status:
+	case []madon.Status, madon.Status, *madon.Status,
+		[]madonext.ThreadStatus, madonext.ThreadStatus, *madonext.ThreadStatus,
+		madonext.ReactionStatus, *madonext.ReactionStatus:
 		objType = "status"
+	case madonext.StatusStats, *madonext.StatusStats:
+		objType = "status_stats"
 	case []madon.StreamEvent, madon.StreamEvent, *madon.StreamEvent:
 		objType = "stream_event"
-	case []madon.Tag, madon.Tag, *madon.Tag:
+	case []madon.Tag, madon.Tag, *madon.Tag,
+		[]madonext.Tag, madonext.Tag, *madonext.Tag:
 		objType = "tag"
+	case []madonext.FamiliarFollowers, madonext.FamiliarFollowers, *madonext.FamiliarFollowers:
+		objType = "familiar_followers"
+	case []madonext.FeaturedTag, madonext.FeaturedTag, *madonext.FeaturedTag:
+		objType = "featured_tag"
+	case madonext.AccountStats, *madonext.AccountStats:
+		objType = "account_stats"
+	case madonext.Preferences, *madonext.Preferences:
+		objType = "preferences"
+	case []madonext.Announcement, madonext.Announcement, *madonext.Announcement:
+		objType = "announcement"
+	case []madonext.Conversation, madonext.Conversation, *madonext.Conversation:
+		objType = "conversation"
+	case []madonext.Rule, madonext.Rule, *madonext.Rule:
+		objType = "rule"
 	}
 
 	var rp *ResourcePrinter
@@ -114,6 +137,7 @@ func (p *ThemePrinter) PrintObj(obj interface{}, w io.Writer, tmpl string) error
 			o := Options{
 				"template":   string(t),
 				"color_mode": p.colorMode,
+				"width":      p.width,
 			}
 			np, err := NewPrinter("template", o)
 			if err != nil {
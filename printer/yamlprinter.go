@@ -9,17 +9,23 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 
 	"github.com/ghodss/yaml"
 )
 
 // YAMLPrinter represents a YAML printer
 type YAMLPrinter struct {
+	multiDoc bool
 }
 
 // NewPrinterYAML returns a YAML ResourcePrinter
+// If the "multidoc" option is set, slice elements are printed as separate
+// YAML documents.
 func NewPrinterYAML(options Options) (*YAMLPrinter, error) {
-	return &YAMLPrinter{}, nil
+	return &YAMLPrinter{
+		multiDoc: options["multidoc"] == "true",
+	}, nil
 }
 
 // PrintObj sends the object as text to the writer
@@ -33,6 +39,17 @@ func (p *YAMLPrinter) PrintObj(obj interface{}, w io.Writer, option string) erro
 	//yamlEncoder := yaml.NewEncoder(w)
 	//return yamlEncoder.Encode(obj)
 
+	if p.multiDoc {
+		if v := reflect.ValueOf(obj); v.Kind() == reflect.Slice {
+			for i := 0; i < v.Len(); i++ {
+				if err := p.printDocument(v.Index(i).Interface(), w); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
 	output, err := yaml.Marshal(obj)
 	if err != nil {
 		return err
@@ -40,3 +57,13 @@ func (p *YAMLPrinter) PrintObj(obj interface{}, w io.Writer, option string) erro
 	_, err = fmt.Fprint(w, string(output))
 	return err
 }
+
+// printDocument prints an object as a separate YAML document
+func (p *YAMLPrinter) printDocument(obj interface{}, w io.Writer) error {
+	output, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "---\n%s", string(output))
+	return err
+}